@@ -0,0 +1,211 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const defaultBatchWorkers = 4
+
+var ErrBatchAborted = errors.New("batch was aborted after a failed job")
+
+type BatchConfig struct {
+	// Workers is the number of jobs processed concurrently.
+	Workers int
+	// FailFast aborts the remaining jobs after the first failed job.
+	FailFast bool
+	// Poll controls how the status of each process is polled.
+	Poll PollConfig
+	// Journal, when set, records the state of every job. Jobs already completed in the journal are not run again,
+	// jobs with a process in progress are resumed, and only jobs without a process or with a failed one are
+	// submitted.
+	Journal *JobJournal
+}
+
+type BatchJob struct {
+	Id      string
+	Process Process
+}
+
+type BatchResult struct {
+	JobId     string
+	ProcessId string
+	Status    Status
+	Output    *Output
+	Err       error
+	StartedAt time.Time
+	Duration  time.Duration
+	// Resumed is set when the process was submitted by a previous run recorded in the journal.
+	Resumed bool
+}
+
+// BatchProcessor runs many processes through create, poll and output with a bounded number of workers.
+type BatchProcessor struct {
+	client Client
+	config BatchConfig
+}
+
+func NewBatchProcessor(client Client, config BatchConfig) *BatchProcessor {
+	return &BatchProcessor{
+		client: client,
+		config: config,
+	}
+}
+
+// Process runs the jobs and streams back a result for each of them. The returned channel is closed once all jobs
+// are finished.
+func (p *BatchProcessor) Process(ctx context.Context, jobs []BatchJob) <-chan BatchResult {
+	input := make(chan BatchJob)
+	go func() {
+		defer close(input)
+		for _, job := range jobs {
+			input <- job
+		}
+	}()
+	return p.ProcessChannel(ctx, input)
+}
+
+// ProcessChannel runs the jobs received from the channel and streams back a result for each of them. The input
+// channel must be closed by the caller, after which the returned channel is closed once all jobs are finished.
+func (p *BatchProcessor) ProcessChannel(ctx context.Context, jobs <-chan BatchJob) <-chan BatchResult {
+	ctx, cancel := context.WithCancel(ctx)
+	results := make(chan BatchResult)
+
+	var (
+		wg      sync.WaitGroup
+		once    sync.Once
+		aborted = make(chan struct{})
+	)
+
+	for i := 0; i < p.workers(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				select {
+				case <-aborted:
+					results <- BatchResult{JobId: job.Id, Err: ErrBatchAborted}
+					continue
+				default:
+				}
+
+				result := p.run(ctx, job)
+				if result.Err != nil && p.config.FailFast {
+					once.Do(func() {
+						close(aborted)
+						cancel()
+					})
+				}
+				results <- result
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		cancel()
+		close(results)
+	}()
+	return results
+}
+
+func (p *BatchProcessor) run(ctx context.Context, job BatchJob) (result BatchResult) {
+	result = BatchResult{
+		JobId:     job.Id,
+		StartedAt: time.Now(),
+	}
+	defer func() {
+		result.Duration = time.Since(result.StartedAt)
+	}()
+
+	if err := ctx.Err(); err != nil {
+		result.Err = NewClientErrorWithCause("job was interrupted by the context", err)
+		return result
+	}
+
+	journal := p.config.Journal
+	if journal != nil {
+		if record, ok := journal.resumable(job); ok {
+			result.ProcessId = record.ProcessId
+			result.Resumed = true
+
+			if record.Status == StatusCompleted {
+				if output, err := journal.output(record); err == nil {
+					result.Status = record.Status
+					result.Output = output
+					return result
+				}
+			}
+		}
+	}
+
+	if result.ProcessId == "" {
+		if err := p.submit(ctx, job, &result); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	status, err := WaitForProcess(ctx, p.client, result.ProcessId, p.config.Poll)
+	// A resumed process may be gone or cancelled together with the interrupted previous run.
+	if err != nil && result.Resumed && ctx.Err() == nil && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrProcessCancelled)) {
+		result.Resumed = false
+		if err := p.submit(ctx, job, &result); err != nil {
+			result.Err = err
+			return result
+		}
+		status, err = WaitForProcess(ctx, p.client, result.ProcessId, p.config.Poll)
+	}
+	result.Status = status
+	if err != nil {
+		var journalErr error
+		if journal != nil && status == StatusInProgress && ctx.Err() != nil {
+			// WaitForProcess cancels the process when the context is cancelled.
+			journalErr = journal.finished(job, result.ProcessId, StatusCancelled)
+		} else if journal != nil && status != StatusInProgress {
+			journalErr = journal.finished(job, result.ProcessId, status)
+		}
+		result.Err = errors.Join(err, journalErr)
+		return result
+	}
+
+	output, err := p.client.GetProcessOutputWithContext(ctx, &GetProcessOutputRequest{
+		Id: result.ProcessId,
+	})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Output = &output.Output
+
+	if journal != nil {
+		result.Err = journal.completed(job, result.ProcessId, result.Output)
+	}
+	return result
+}
+
+func (p *BatchProcessor) submit(ctx context.Context, job BatchJob, result *BatchResult) error {
+	created, err := p.client.CreateProcessWithContext(ctx, &CreateProcessRequest{
+		Process: job.Process,
+	})
+	if err != nil {
+		return err
+	}
+	result.ProcessId = created.Id
+
+	if p.config.Journal != nil {
+		return p.config.Journal.submitted(job, created.Id)
+	}
+	return nil
+}
+
+func (p *BatchProcessor) workers() int {
+	if p.config.Workers > 0 {
+		return p.config.Workers
+	}
+	return defaultBatchWorkers
+}
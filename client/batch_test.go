@@ -0,0 +1,131 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatchProcessor(t *testing.T) {
+
+	t.Run("BatchProcessor returns a result for every job", func(t *testing.T) {
+		ts := processServer(StatusCompleted)
+		defer ts.Close()
+
+		processor := NewBatchProcessor(client(ts.URL), BatchConfig{
+			Workers: 2,
+			Poll:    pollConfig(),
+		})
+
+		jobs := []BatchJob{
+			{Id: "1", Process: Process{Mode: ModeDocument, Language: LanguageGo}},
+			{Id: "2", Process: Process{Mode: ModeDocument, Language: LanguageGo}},
+			{Id: "3", Process: Process{Mode: ModeDocument, Language: LanguageGo}},
+		}
+
+		got := map[string]BatchResult{}
+		for result := range processor.Process(context.Background(), jobs) {
+			got[result.JobId] = result
+		}
+
+		if len(got) != len(jobs) {
+			t.Fatalf("Results count was incorrect got %d", len(got))
+		}
+		for _, job := range jobs {
+			result := got[job.Id]
+			if result.Err != nil {
+				t.Fatalf("Job %s failed with an error %v", job.Id, result.Err)
+			}
+			if result.ProcessId != "id" || result.Status != StatusCompleted {
+				t.Fatalf("Job %s result was incorrect got %s %s", job.Id, result.ProcessId, result.Status)
+			}
+			if result.Output == nil || result.Output.Source != "source" {
+				t.Fatalf("Job %s output was incorrect got %v", job.Id, result.Output)
+			}
+		}
+	})
+
+	t.Run("BatchProcessor continues on error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/process":
+				if body, _ := io.ReadAll(r.Body); strings.Contains(string(body), "fail") {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprintln(w, `{"code":"BAD_REQUEST"}`)
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintln(w, `{"id": "id"}`)
+			case "/process/status":
+				fmt.Fprintln(w, `{"status": "COMPLETED"}`)
+			case "/process/output":
+				fmt.Fprintln(w, `{"output": {"source": "source"}}`)
+			}
+		}))
+		defer ts.Close()
+
+		processor := NewBatchProcessor(client(ts.URL), BatchConfig{
+			Workers: 1,
+			Poll:    pollConfig(),
+		})
+
+		jobs := []BatchJob{
+			{Id: "1", Process: Process{Input: Input{Source: "fail"}}},
+			{Id: "2", Process: Process{Input: Input{Source: "ok"}}},
+		}
+
+		failed, succeeded := 0, 0
+		for result := range processor.Process(context.Background(), jobs) {
+			if result.Err != nil {
+				failed++
+			} else {
+				succeeded++
+			}
+		}
+
+		if failed != 1 || succeeded != 1 {
+			t.Fatalf("Results were incorrect got %d failed and %d succeeded", failed, succeeded)
+		}
+	})
+
+	t.Run("BatchProcessor aborts remaining jobs on fail fast", func(t *testing.T) {
+		ts := processServer(StatusFailed)
+		defer ts.Close()
+
+		processor := NewBatchProcessor(client(ts.URL), BatchConfig{
+			Workers:  1,
+			FailFast: true,
+			Poll:     pollConfig(),
+		})
+
+		jobs := make(chan BatchJob, 3)
+		for i := 0; i < 3; i++ {
+			jobs <- BatchJob{Id: fmt.Sprint(i)}
+		}
+		close(jobs)
+
+		var got []BatchResult
+		for result := range processor.ProcessChannel(context.Background(), jobs) {
+			got = append(got, result)
+		}
+
+		if len(got) != 3 {
+			t.Fatalf("Results count was incorrect got %d", len(got))
+		}
+		if !errors.Is(got[0].Err, ErrProcessFailed) {
+			t.Fatalf("First job was expected to fail got %v", got[0].Err)
+		}
+		for _, result := range got[1:] {
+			if !errors.Is(result.Err, ErrBatchAborted) {
+				t.Fatalf("Job %s was expected to be aborted got %v", result.JobId, result.Err)
+			}
+		}
+	})
+}
@@ -0,0 +1,359 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const cachedProcessIdPrefix = "cached-"
+
+// Cache stores process outputs by the CacheKey of the process. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the output stored under the key, if present and not expired.
+	Get(key string) (*Output, bool, error)
+	// Set stores the output under the key, a zero ttl never expires.
+	Set(key string, output *Output, ttl time.Duration) error
+	// Delete removes the output stored under the key.
+	Delete(key string) error
+}
+
+// CacheKey returns the hash of the full process and the SDK version.
+func CacheKey(process Process) string {
+	data, _ := json.Marshal(struct {
+		Version string  `json:"version"`
+		Process Process `json:"process"`
+	}{
+		Version: Version,
+		Process: process,
+	})
+	return hash(data)
+}
+
+// CachingClient serves the output of previously completed processes from the cache without calling the API.
+// Cached processes are reported with an id prefixed by "cached-" and StatusCompleted, their output is read from the
+// cache for as long as it is cached. Cache failures are treated as cache misses.
+//
+// The cache key of a created process is kept until its output is fetched, it fails or it is cancelled. Processes
+// whose status is not checked within an hour, e.g. after the wait for them was abandoned, are forgotten.
+type CachingClient struct {
+	client  Client
+	cache   Cache
+	ttl     time.Duration
+	mu      sync.Mutex
+	pending map[string]*pendingProcess
+}
+
+type pendingProcess struct {
+	key       string
+	checkedAt time.Time
+}
+
+func NewCachingClient(client Client, cache Cache, ttl time.Duration) *CachingClient {
+	return &CachingClient{
+		client:  client,
+		cache:   cache,
+		ttl:     ttl,
+		pending: map[string]*pendingProcess{},
+	}
+}
+
+// Invalidate removes the cached output of the process.
+func (c *CachingClient) Invalidate(process Process) error {
+	return c.cache.Delete(CacheKey(process))
+}
+
+func (c *CachingClient) CreateProcess(request *CreateProcessRequest) (*CreateProcessResponse, error) {
+	return c.CreateProcessWithContext(context.Background(), request)
+}
+
+func (c *CachingClient) GetProcessStatus(request *GetProcessStatusRequest) (*GetProcessStatusResponse, error) {
+	return c.GetProcessStatusWithContext(context.Background(), request)
+}
+
+func (c *CachingClient) GetProcessOutput(request *GetProcessOutputRequest) (*GetProcessOutputResponse, error) {
+	return c.GetProcessOutputWithContext(context.Background(), request)
+}
+
+func (c *CachingClient) CancelProcess(request *CancelProcessRequest) (*CancelProcessResponse, error) {
+	return c.CancelProcessWithContext(context.Background(), request)
+}
+
+func (c *CachingClient) CreateProcessWithContext(ctx context.Context, request *CreateProcessRequest) (*CreateProcessResponse, error) {
+	if request == nil {
+		request = &CreateProcessRequest{}
+	}
+
+	key := CacheKey(request.Process)
+	if _, ok, err := c.cache.Get(key); err == nil && ok {
+		return &CreateProcessResponse{
+			Id: cachedProcessIdPrefix + key,
+		}, nil
+	}
+
+	response, err := c.client.CreateProcessWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	for id, p := range c.pending {
+		if now.Sub(p.checkedAt) > processTrackingTTL {
+			delete(c.pending, id)
+		}
+	}
+	c.pending[response.Id] = &pendingProcess{key: key, checkedAt: now}
+	c.mu.Unlock()
+
+	return response, nil
+}
+
+func (c *CachingClient) GetProcessStatusWithContext(ctx context.Context, request *GetProcessStatusRequest) (*GetProcessStatusResponse, error) {
+	if request == nil {
+		return c.client.GetProcessStatusWithContext(ctx, request)
+	}
+
+	if key, ok := cachedProcessKey(request.Id); ok {
+		if _, err := c.cached(key); err != nil {
+			return nil, err
+		}
+		return &GetProcessStatusResponse{
+			Status: StatusCompleted,
+		}, nil
+	}
+
+	response, err := c.client.GetProcessStatusWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if response.Status.IsTerminal() && response.Status != StatusCompleted {
+		delete(c.pending, request.Id)
+	} else if p, ok := c.pending[request.Id]; ok {
+		p.checkedAt = time.Now()
+	}
+	c.mu.Unlock()
+	return response, nil
+}
+
+func (c *CachingClient) GetProcessOutputWithContext(ctx context.Context, request *GetProcessOutputRequest) (*GetProcessOutputResponse, error) {
+	if request == nil {
+		return c.client.GetProcessOutputWithContext(ctx, request)
+	}
+
+	if key, ok := cachedProcessKey(request.Id); ok {
+		output, err := c.cached(key)
+		if err != nil {
+			return nil, err
+		}
+		return &GetProcessOutputResponse{
+			Output: *output,
+		}, nil
+	}
+
+	response, err := c.client.GetProcessOutputWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	p, ok := c.pending[request.Id]
+	delete(c.pending, request.Id)
+	c.mu.Unlock()
+
+	if ok {
+		output := response.Output
+		c.cache.Set(p.key, &output, c.ttl)
+	}
+	return response, nil
+}
+
+func (c *CachingClient) CancelProcessWithContext(ctx context.Context, request *CancelProcessRequest) (*CancelProcessResponse, error) {
+	if request == nil {
+		return c.client.CancelProcessWithContext(ctx, request)
+	}
+
+	if _, ok := cachedProcessKey(request.Id); ok {
+		return &CancelProcessResponse{}, nil
+	}
+
+	c.mu.Lock()
+	delete(c.pending, request.Id)
+	c.mu.Unlock()
+
+	return c.client.CancelProcessWithContext(ctx, request)
+}
+
+// cached returns the cached output, failing with ErrNotFound when it is no longer cached.
+func (c *CachingClient) cached(key string) (*Output, error) {
+	output, ok, err := c.cache.Get(key)
+	if err != nil || !ok {
+		return nil, NewClientErrorWithCause("cached output is no longer available", ErrNotFound)
+	}
+	return output, nil
+}
+
+func cachedProcessKey(id string) (string, bool) {
+	return strings.CutPrefix(id, cachedProcessIdPrefix)
+}
+
+type memoryCacheEntry struct {
+	key     string
+	output  Output
+	expires time.Time
+}
+
+type memoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// NewMemoryCache creates an in-memory Cache evicting the least recently used outputs above the capacity.
+func NewMemoryCache(capacity int) Cache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &memoryCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (c *memoryCache) Get(key string) (*Output, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*memoryCacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	output := entry.output
+	return &output, true, nil
+}
+
+func (c *memoryCache) Set(key string, output *Output, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &memoryCacheEntry{
+		key:     key,
+		output:  *output,
+		expires: expiration(ttl),
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+	return nil
+}
+
+func (c *memoryCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+type directoryCacheEntry struct {
+	Output  Output    `json:"output"`
+	Expires time.Time `json:"expires"`
+}
+
+type directoryCache struct {
+	dir string
+}
+
+// NewDirectoryCache creates a Cache storing every output as a file in the directory.
+func NewDirectoryCache(dir string) Cache {
+	return &directoryCache{
+		dir: dir,
+	}
+}
+
+func (c *directoryCache) Get(key string) (*Output, bool, error) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, NewClientErrorWithCause("failed to read cache entry", err)
+	}
+
+	var entry directoryCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false, NewClientErrorWithCause("failed to parse cache entry", err)
+	}
+	if !entry.Expires.IsZero() && time.Now().After(entry.Expires) {
+		os.Remove(c.path(key))
+		return nil, false, nil
+	}
+	return &entry.Output, true, nil
+}
+
+func (c *directoryCache) Set(key string, output *Output, ttl time.Duration) error {
+	data, err := json.Marshal(&directoryCacheEntry{
+		Output:  *output,
+		Expires: expiration(ttl),
+	})
+	if err != nil {
+		return NewClientErrorWithCause("failed to serialize cache entry", err)
+	}
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return NewClientErrorWithCause("failed to create cache directory", err)
+	}
+	return writeAtomic(c.path(key), data, 0600)
+}
+
+func (c *directoryCache) Delete(key string) error {
+	if err := os.Remove(c.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return NewClientErrorWithCause("failed to delete cache entry", err)
+	}
+	return nil
+}
+
+func (c *directoryCache) path(key string) string {
+	return filepath.Join(c.dir, filepath.Base(key)+".json")
+}
+
+func expiration(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+
+	process := Process{
+		Mode:     ModeDocument,
+		Language: LanguageGo,
+		Input: Input{
+			Source: "package main",
+		},
+	}
+
+	t.Run("CacheKey depends on the full process", func(t *testing.T) {
+		other := process
+		other.Mode = ModeUnitTest
+
+		if CacheKey(process) != CacheKey(process) {
+			t.Fatalf("CacheKey was expected to be stable")
+		}
+		if CacheKey(process) == CacheKey(other) {
+			t.Fatalf("CacheKey was expected to differ for different modes")
+		}
+	})
+
+	t.Run("CachingClient serves cached output without calling the API", func(t *testing.T) {
+		var creates int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/process":
+				atomic.AddInt32(&creates, 1)
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintln(w, `{"id": "id"}`)
+			case "/process/status":
+				fmt.Fprintln(w, `{"status": "COMPLETED"}`)
+			case "/process/output":
+				fmt.Fprintln(w, `{"output": {"source": "source"}}`)
+			}
+		}))
+		defer ts.Close()
+
+		caching := NewCachingClient(client(ts.URL), NewMemoryCache(10), time.Hour)
+
+		for i := 0; i < 2; i++ {
+			got, err := RunProcess(context.Background(), caching, &CreateProcessRequest{Process: process}, pollConfig())
+			if err != nil {
+				t.Fatalf("RunProcess failed with an error %v", err)
+			}
+			if got.Source != "source" {
+				t.Fatalf("Output source was incorrect got %s", got.Source)
+			}
+		}
+		if creates != 1 {
+			t.Fatalf("Process was expected to be created once got %d", creates)
+		}
+
+		if err := caching.Invalidate(process); err != nil {
+			t.Fatalf("Invalidate failed with an error %v", err)
+		}
+		if _, err := RunProcess(context.Background(), caching, &CreateProcessRequest{Process: process}, pollConfig()); err != nil {
+			t.Fatalf("RunProcess failed with an error %v", err)
+		}
+		if creates != 2 {
+			t.Fatalf("Process was expected to be created again after invalidation got %d", creates)
+		}
+	})
+
+	t.Run("CachingClient serves cached output more than once", func(t *testing.T) {
+		cache := NewMemoryCache(10)
+		cache.Set(CacheKey(process), &Output{Source: "source"}, 0)
+		caching := NewCachingClient(client("http://127.0.0.1:0"), cache, time.Hour)
+
+		created, err := caching.CreateProcess(&CreateProcessRequest{Process: process})
+		if err != nil {
+			t.Fatalf("CreateProcess failed with an error %v", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			got, err := caching.GetProcessOutput(&GetProcessOutputRequest{Id: created.Id})
+			if err != nil {
+				t.Fatalf("GetProcessOutput failed with an error %v", err)
+			}
+			if got.Output.Source != "source" {
+				t.Fatalf("Output source was incorrect got %s", got.Output.Source)
+			}
+		}
+
+		cache.Delete(CacheKey(process))
+		if _, err := caching.GetProcessOutput(&GetProcessOutputRequest{Id: created.Id}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Error was expected to be ErrNotFound got %v", err)
+		}
+	})
+
+	t.Run("CachingClient forgets failed processes", func(t *testing.T) {
+		ts := processServer(StatusFailed)
+		defer ts.Close()
+
+		caching := NewCachingClient(client(ts.URL), NewMemoryCache(10), time.Hour)
+
+		created, err := caching.CreateProcess(&CreateProcessRequest{Process: process})
+		if err != nil {
+			t.Fatalf("CreateProcess failed with an error %v", err)
+		}
+		if _, err := caching.GetProcessStatus(&GetProcessStatusRequest{Id: created.Id}); err != nil {
+			t.Fatalf("GetProcessStatus failed with an error %v", err)
+		}
+
+		if len(caching.pending) != 0 {
+			t.Fatalf("Pending processes were expected to be empty got %v", caching.pending)
+		}
+	})
+
+	t.Run("CachingClient forgets abandoned processes", func(t *testing.T) {
+		ts := processServer(StatusInProgress)
+		defer ts.Close()
+
+		caching := NewCachingClient(client(ts.URL), NewMemoryCache(10), time.Hour)
+
+		caching.pending["abandoned"] = &pendingProcess{key: "key", checkedAt: time.Now().Add(-2 * processTrackingTTL)}
+
+		if _, err := caching.CreateProcess(&CreateProcessRequest{Process: process}); err != nil {
+			t.Fatalf("CreateProcess failed with an error %v", err)
+		}
+
+		if _, ok := caching.pending["abandoned"]; ok || len(caching.pending) != 1 {
+			t.Fatalf("Abandoned process was expected to be forgotten got %v", caching.pending)
+		}
+	})
+
+	t.Run("MemoryCache evicts least recently used output", func(t *testing.T) {
+		cache := NewMemoryCache(2)
+		cache.Set("a", &Output{Source: "a"}, 0)
+		cache.Set("b", &Output{Source: "b"}, 0)
+		cache.Get("a")
+		cache.Set("c", &Output{Source: "c"}, 0)
+
+		if _, ok, _ := cache.Get("b"); ok {
+			t.Fatalf("Output b was expected to be evicted")
+		}
+		if got, ok, _ := cache.Get("a"); !ok || got.Source != "a" {
+			t.Fatalf("Output a was expected to be cached")
+		}
+	})
+
+	t.Run("MemoryCache expires output", func(t *testing.T) {
+		cache := NewMemoryCache(2)
+		cache.Set("a", &Output{Source: "a"}, time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+
+		if _, ok, _ := cache.Get("a"); ok {
+			t.Fatalf("Output was expected to expire")
+		}
+	})
+
+	t.Run("DirectoryCache stores output on disk", func(t *testing.T) {
+		dir := t.TempDir()
+		key := CacheKey(process)
+
+		if err := NewDirectoryCache(dir).Set(key, &Output{Source: "source"}, time.Hour); err != nil {
+			t.Fatalf("Set failed with an error %v", err)
+		}
+
+		cache := NewDirectoryCache(dir)
+		got, ok, err := cache.Get(key)
+		if err != nil || !ok || got.Source != "source" {
+			t.Fatalf("Output was expected to be cached got %v %v %v", got, ok, err)
+		}
+
+		cache.Delete(key)
+		if _, ok, _ := cache.Get(key); ok {
+			t.Fatalf("Output was expected to be deleted")
+		}
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LanguageCapabilities describes what the API supports for a language.
+type LanguageCapabilities struct {
+	Language Language
+	// Modes are the supported modes.
+	Modes []Mode
+	// Versions are the language versions accepted as Options.LanguageVersion, e.g. as the ModeMigrateSyntax target.
+	// Any version is accepted when empty.
+	Versions []string
+	// Frameworks are the test frameworks accepted as Options.Framework by ModeUnitTest. Any framework is accepted
+	// when empty.
+	Frameworks []string
+}
+
+// SupportsMode returns true when the mode is supported for the language.
+func (c LanguageCapabilities) SupportsMode(mode Mode) bool {
+	return contains(c.Modes, mode)
+}
+
+// SupportsVersion returns true when the version is accepted for the language, ignoring case.
+func (c LanguageCapabilities) SupportsVersion(version string) bool {
+	return len(c.Versions) == 0 || containsFold(c.Versions, version)
+}
+
+// SupportsFramework returns true when the test framework is accepted for the language, ignoring case.
+func (c LanguageCapabilities) SupportsFramework(framework string) bool {
+	return len(c.Frameworks) == 0 || containsFold(c.Frameworks, framework)
+}
+
+func (c LanguageCapabilities) copy() LanguageCapabilities {
+	return LanguageCapabilities{
+		Language:   c.Language,
+		Modes:      append([]Mode(nil), c.Modes...),
+		Versions:   append([]string(nil), c.Versions...),
+		Frameworks: append([]string(nil), c.Frameworks...),
+	}
+}
+
+// CapabilityRegistry holds the capabilities of every language and the limits of every process. It is safe for
+// concurrent use.
+type CapabilityRegistry struct {
+	mu            sync.RWMutex
+	capabilities  map[Language]LanguageCapabilities
+	maxSourceSize int
+	modifyModes   map[Mode]bool
+}
+
+// NewCapabilityRegistry creates a registry with the capabilities, MaxSourceSize and ModeDocument as the only mode
+// supporting Options.Modify.
+func NewCapabilityRegistry(capabilities ...LanguageCapabilities) *CapabilityRegistry {
+	r := &CapabilityRegistry{
+		capabilities:  map[Language]LanguageCapabilities{},
+		maxSourceSize: MaxSourceSize,
+		modifyModes:   copyModes(modifyModes),
+	}
+	for _, c := range capabilities {
+		r.Register(c)
+	}
+	return r
+}
+
+// Register adds the capabilities of a language, replacing the previously registered ones.
+func (r *CapabilityRegistry) Register(capabilities LanguageCapabilities) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.capabilities[capabilities.Language] = capabilities.copy()
+}
+
+// Capabilities returns the capabilities of the language.
+func (r *CapabilityRegistry) Capabilities(language Language) (LanguageCapabilities, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.capabilities[language]
+	if !ok {
+		return LanguageCapabilities{}, false
+	}
+	return c.copy(), true
+}
+
+// Languages returns the registered languages sorted by name.
+func (r *CapabilityRegistry) Languages() []Language {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	languages := make([]Language, 0, len(r.capabilities))
+	for language := range r.capabilities {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i] < languages[j]
+	})
+	return languages
+}
+
+// LanguagesForMode returns the registered languages supporting the mode sorted by name.
+func (r *CapabilityRegistry) LanguagesForMode(mode Mode) []Language {
+	var languages []Language
+	for _, language := range r.Languages() {
+		if c, ok := r.Capabilities(language); ok && c.SupportsMode(mode) {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
+
+// SetMaxSourceSize sets the largest source in bytes accepted by the validation.
+func (r *CapabilityRegistry) SetMaxSourceSize(size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.maxSourceSize = size
+}
+
+// MaxSourceSize returns the largest source in bytes accepted by the validation.
+func (r *CapabilityRegistry) MaxSourceSize() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.maxSourceSize
+}
+
+// SetModifyModes sets the modes that can replace existing code as requested by Options.Modify.
+func (r *CapabilityRegistry) SetModifyModes(modes ...Mode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.modifyModes = map[Mode]bool{}
+	for _, mode := range modes {
+		r.modifyModes[mode] = true
+	}
+}
+
+// SupportsModify returns true when the mode can replace existing code as requested by Options.Modify.
+func (r *CapabilityRegistry) SupportsModify(mode Mode) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.modifyModes[mode]
+}
+
+// DefaultCapabilities is used by the validation when no other registry is configured. The API neither publishes nor
+// serves the modes, versions and frameworks it supports for a language, so DefaultCapabilities allows all of them for
+// every known language and only the limits of the registry are checked. Register the capabilities of a language to
+// restrict them.
+var DefaultCapabilities = defaultCapabilities()
+
+func defaultCapabilities() *CapabilityRegistry {
+	r := NewCapabilityRegistry()
+	for _, language := range Languages() {
+		r.Register(LanguageCapabilities{
+			Language: language,
+			Modes:    Modes(),
+		})
+	}
+	return r
+}
+
+func copyModes(modes map[Mode]bool) map[Mode]bool {
+	c := make(map[Mode]bool, len(modes))
+	for mode := range modes {
+		c[mode] = true
+	}
+	return c
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) capabilities() *CapabilityRegistry {
+	if c.Capabilities != nil {
+		return c.Capabilities
+	}
+	return DefaultCapabilities
+}
@@ -0,0 +1,176 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+
+	registry := NewCapabilityRegistry(
+		LanguageCapabilities{
+			Language: LanguageGo,
+			Modes:    []Mode{ModeDocument, ModeUnitTest},
+		},
+		LanguageCapabilities{
+			Language:   LanguageJava,
+			Modes:      []Mode{ModeDocument, ModeUnitTest, ModeMigrateSyntax},
+			Versions:   []string{"17", "21"},
+			Frameworks: []string{"JUnit5"},
+		},
+	)
+
+	t.Run("Default capabilities allow everything for every language", func(t *testing.T) {
+		languages := DefaultCapabilities.Languages()
+
+		if len(languages) != len(Languages()) {
+			t.Fatalf("Languages were incorrect got %v", languages)
+		}
+		for _, language := range Languages() {
+			c, ok := DefaultCapabilities.Capabilities(language)
+			if !ok || len(c.Modes) != len(Modes()) || !c.SupportsVersion("22") || !c.SupportsFramework("JUnit") {
+				t.Fatalf("Capabilities of %s were incorrect got %v", language, c)
+			}
+		}
+	})
+
+	t.Run("Languages for mode excludes unsupported languages", func(t *testing.T) {
+		languages := registry.LanguagesForMode(ModeMigrateSyntax)
+
+		if contains(languages, LanguageGo) || !contains(languages, LanguageJava) {
+			t.Fatalf("Languages were incorrect got %v", languages)
+		}
+	})
+
+	t.Run("Versions and frameworks ignore case and accept anything when empty", func(t *testing.T) {
+		c := LanguageCapabilities{Language: LanguageJava, Versions: []string{"17"}}
+
+		if !c.SupportsVersion("17") || c.SupportsVersion("18") {
+			t.Fatalf("Versions were incorrect")
+		}
+		if !c.SupportsFramework("anything") {
+			t.Fatalf("Frameworks were incorrect")
+		}
+
+		c.Frameworks = []string{"JUnit5"}
+		if !c.SupportsFramework("junit5") || c.SupportsFramework("TestNG") {
+			t.Fatalf("Frameworks were incorrect")
+		}
+	})
+
+	t.Run("Register replaces capabilities and copies them", func(t *testing.T) {
+		modes := []Mode{ModeCode}
+		r := NewCapabilityRegistry(LanguageCapabilities{Language: LanguageGo, Modes: []Mode{ModeDocument}})
+		r.Register(LanguageCapabilities{Language: LanguageGo, Modes: modes})
+		modes[0] = ModeDocument
+
+		c, _ := r.Capabilities(LanguageGo)
+		if !reflect.DeepEqual(c.Modes, []Mode{ModeCode}) {
+			t.Fatalf("Modes were incorrect got %v", c.Modes)
+		}
+	})
+
+	t.Run("Validation rejects unsupported combinations", func(t *testing.T) {
+		version := "Go 2"
+		framework := "JUnit5"
+		process := Process{
+			Mode:     ModeMigrateSyntax,
+			Language: LanguageGo,
+			Input:    Input{Source: "package main"},
+			Options:  &Options{LanguageVersion: &version},
+		}
+
+		var validationErr *ValidationError
+		if err := process.ValidateWith(registry); !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 ||
+			validationErr.Errors[0].Field != "process.mode" {
+			t.Fatalf("Error was incorrect got %v", err)
+		}
+
+		process.Mode = ModeUnitTest
+		process.Language = LanguageJava
+		process.Options = &Options{LanguageVersion: &version, Framework: &framework}
+		if err := process.ValidateWith(registry); !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 ||
+			validationErr.Errors[0].Field != "process.options.languageVersion" {
+			t.Fatalf("Error was incorrect got %v", err)
+		}
+
+		framework = "pytest"
+		version = "21"
+		if err := process.ValidateWith(registry); !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 ||
+			validationErr.Errors[0].Field != "process.options.framework" {
+			t.Fatalf("Error was incorrect got %v", err)
+		}
+	})
+
+	t.Run("Validation skips languages missing from the registry", func(t *testing.T) {
+		process := Process{
+			Mode:     ModeMigrateSyntax,
+			Language: LanguageGo,
+			Input:    Input{Source: "package main"},
+		}
+
+		if err := process.ValidateWith(NewCapabilityRegistry()); err != nil {
+			t.Fatalf("Error was not expected got %v", err)
+		}
+	})
+
+	t.Run("Registry overrides source size limit and modify modes", func(t *testing.T) {
+		modify := ModifyReplace
+		process := Process{
+			Mode:     ModeUnitTest,
+			Language: LanguageGo,
+			Input:    Input{Source: "package main"},
+			Options:  &Options{Modify: &modify},
+		}
+
+		registry := NewCapabilityRegistry()
+		registry.SetModifyModes(ModeDocument, ModeUnitTest)
+		if err := process.ValidateWith(registry); err != nil {
+			t.Fatalf("Error was not expected got %v", err)
+		}
+
+		registry.SetMaxSourceSize(4)
+		var validationErr *ValidationError
+		if err := process.ValidateWith(registry); !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 ||
+			validationErr.Errors[0].Field != "process.input.source" {
+			t.Fatalf("Error was incorrect got %v", err)
+		}
+	})
+
+	t.Run("Client validates with configured capabilities", func(t *testing.T) {
+		var calls int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+		}))
+		defer ts.Close()
+
+		endpoint := ts.URL
+		c := NewClient(Config{
+			ApiKey:           "ABCDE-GHIJK-LMNOP-QRSTU-1",
+			Endpoint:         &endpoint,
+			ValidateRequests: true,
+			Capabilities: NewCapabilityRegistry(LanguageCapabilities{
+				Language: LanguageGo,
+				Modes:    []Mode{ModeCode},
+			}),
+		})
+
+		_, err := c.CreateProcess(&CreateProcessRequest{Process: Process{
+			Mode:     ModeDocument,
+			Language: LanguageGo,
+			Input:    Input{Source: "package main"},
+		}})
+		if !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("Request was expected to fail validation got %v", err)
+		}
+		if calls != 0 {
+			t.Fatalf("API was expected not to be called got %d calls", calls)
+		}
+	})
+}
@@ -4,6 +4,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -27,12 +28,33 @@ type Client interface {
 	CreateProcess(request *CreateProcessRequest) (*CreateProcessResponse, error)
 	GetProcessStatus(request *GetProcessStatusRequest) (*GetProcessStatusResponse, error)
 	GetProcessOutput(request *GetProcessOutputRequest) (*GetProcessOutputResponse, error)
+	CancelProcess(request *CancelProcessRequest) (*CancelProcessResponse, error)
+
+	CreateProcessWithContext(ctx context.Context, request *CreateProcessRequest) (*CreateProcessResponse, error)
+	GetProcessStatusWithContext(ctx context.Context, request *GetProcessStatusRequest) (*GetProcessStatusResponse, error)
+	GetProcessOutputWithContext(ctx context.Context, request *GetProcessOutputRequest) (*GetProcessOutputResponse, error)
+	CancelProcessWithContext(ctx context.Context, request *CancelProcessRequest) (*CancelProcessResponse, error)
+}
+
+type operation struct {
+	name       string
+	path       string
+	idempotent bool
 }
 
+var (
+	operationCreateProcess    = operation{name: OperationCreateProcess, path: "/process", idempotent: false}
+	operationGetProcessStatus = operation{name: OperationGetProcessStatus, path: "/process/status", idempotent: true}
+	operationGetProcessOutput = operation{name: OperationGetProcessOutput, path: "/process/output", idempotent: true}
+	operationCancelProcess    = operation{name: OperationCancelProcess, path: "/process/cancel", idempotent: true}
+)
+
 type HttpClient struct {
 	Client
-	config Config
-	client *http.Client
+	config    Config
+	client    *http.Client
+	handler   Handler
+	processes processTracker
 }
 
 func NewClient(config Config) Client {
@@ -46,7 +68,7 @@ func NewClient(config Config) Client {
 		requestTimeout = *config.RequestTimeout
 	}
 
-	return &HttpClient{
+	c := &HttpClient{
 		config: config,
 		client: &http.Client{
 			Transport: &http.Transport{
@@ -58,105 +80,200 @@ func NewClient(config Config) Client {
 			Timeout: requestTimeout,
 		},
 	}
+	c.handler = chainMiddlewares(config.Middlewares, c.send)
+	return c
 }
 
 func (c *HttpClient) CreateProcess(request *CreateProcessRequest) (*CreateProcessResponse, error) {
+	return c.CreateProcessWithContext(context.Background(), request)
+}
+
+func (c *HttpClient) GetProcessStatus(request *GetProcessStatusRequest) (*GetProcessStatusResponse, error) {
+	return c.GetProcessStatusWithContext(context.Background(), request)
+}
+
+func (c *HttpClient) GetProcessOutput(request *GetProcessOutputRequest) (*GetProcessOutputResponse, error) {
+	return c.GetProcessOutputWithContext(context.Background(), request)
+}
+
+func (c *HttpClient) CancelProcess(request *CancelProcessRequest) (*CancelProcessResponse, error) {
+	return c.CancelProcessWithContext(context.Background(), request)
+}
+
+func (c *HttpClient) CreateProcessWithContext(ctx context.Context, request *CreateProcessRequest) (*CreateProcessResponse, error) {
 	if request == nil {
 		request = &CreateProcessRequest{}
 	}
-
-	body, err := json.Marshal(request)
-	if err != nil {
-		return nil, NewClientErrorWithCause("failed to serialize request payload", err)
+	if c.config.ValidateRequests {
+		if err := request.ValidateWith(c.config.capabilities()); err != nil {
+			return nil, err
+		}
 	}
 
-	resp, err := c.doRequest(http.MethodPost, "/process", body)
-	if err != nil {
-		return nil, NewClientErrorWithCause("failed to make HTTP request", err)
+	response := &CreateProcessResponse{}
+	if err := c.call(ctx, operationCreateProcess, request, response); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return response, nil
+}
 
-	if !c.isSuccess(resp) {
-		return nil, c.handleError(resp)
+func (c *HttpClient) GetProcessStatusWithContext(ctx context.Context, request *GetProcessStatusRequest) (*GetProcessStatusResponse, error) {
+	if request == nil {
+		request = &GetProcessStatusRequest{}
 	}
 
-	response, err := c.handleResponse(resp, &CreateProcessResponse{})
-	if err != nil {
+	response := &GetProcessStatusResponse{}
+	if err := c.call(ctx, operationGetProcessStatus, request, response); err != nil {
 		return nil, err
 	}
-	return response.(*CreateProcessResponse), nil
+	return response, nil
 }
 
-func (c *HttpClient) GetProcessStatus(request *GetProcessStatusRequest) (*GetProcessStatusResponse, error) {
+func (c *HttpClient) GetProcessOutputWithContext(ctx context.Context, request *GetProcessOutputRequest) (*GetProcessOutputResponse, error) {
 	if request == nil {
-		request = &GetProcessStatusRequest{}
-	}
-
-	body, err := json.Marshal(request)
-	if err != nil {
-		return nil, NewClientErrorWithCause("failed to serialize request payload", err)
+		request = &GetProcessOutputRequest{}
 	}
 
-	resp, err := c.doRequest(http.MethodPost, "/process/status", body)
-	if err != nil {
-		return nil, NewClientErrorWithCause("failed to make HTTP request", err)
+	response := &GetProcessOutputResponse{}
+	if err := c.call(ctx, operationGetProcessOutput, request, response); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return response, nil
+}
 
-	if !c.isSuccess(resp) {
-		return nil, c.handleError(resp)
+func (c *HttpClient) CancelProcessWithContext(ctx context.Context, request *CancelProcessRequest) (*CancelProcessResponse, error) {
+	if request == nil {
+		request = &CancelProcessRequest{}
 	}
 
-	response, err := c.handleResponse(resp, &GetProcessStatusResponse{})
-	if err != nil {
+	response := &CancelProcessResponse{}
+	if err := c.call(ctx, operationCancelProcess, request, response); err != nil {
 		return nil, err
 	}
-	return response.(*GetProcessStatusResponse), nil
+	return response, nil
 }
 
-func (c *HttpClient) GetProcessOutput(request *GetProcessOutputRequest) (*GetProcessOutputResponse, error) {
-	if request == nil {
-		request = &GetProcessOutputRequest{}
+func (c *HttpClient) call(ctx context.Context, op operation, request interface{}, response interface{}) (err error) {
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
+	parent := ctx
+	ctx, span := c.startSpan(ctx, op, request)
+	defer func() {
+		c.endSpan(span, response, err)
+		if err != nil {
+			c.logError(ctx, op, request, err)
+		} else {
+			c.trackProcess(parent, request, response)
+		}
+	}()
+
 	body, err := json.Marshal(request)
 	if err != nil {
-		return nil, NewClientErrorWithCause("failed to serialize request payload", err)
+		return NewClientErrorWithCause("failed to serialize request payload", err)
+	}
+	body, encoding, err := c.config.compressBody(body)
+	if err != nil {
+		return err
 	}
 
-	resp, err := c.doRequest(http.MethodPost, "/process/output", body)
+	resp, err := c.doRequestWithRetry(ctx, op, request, body, encoding)
 	if err != nil {
-		return nil, NewClientErrorWithCause("failed to make HTTP request", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return NewClientErrorWithCause("request was interrupted by the context", ctxErr)
+		}
+		return NewClientErrorWithCause("failed to make HTTP request", err)
 	}
 	defer resp.Body.Close()
 
-	if !c.isSuccess(resp) {
-		return nil, c.handleError(resp)
+	span.SetAttributes(Attribute{Key: AttributeHttpStatusCode, Value: resp.StatusCode})
+	if requestId := resp.Header.Get(headerRequestId); requestId != "" {
+		span.SetAttributes(Attribute{Key: AttributeRequestId, Value: requestId})
 	}
 
-	response, err := c.handleResponse(resp, &GetProcessOutputResponse{})
-	if err != nil {
-		return nil, err
+	if !c.isSuccess(resp) {
+		return c.handleError(resp)
 	}
-	return response.(*GetProcessOutputResponse), nil
+
+	_, err = c.handleResponse(resp, response)
+	return err
 }
 
 func (c *HttpClient) isSuccess(resp *http.Response) bool {
 	return resp.StatusCode >= 200 && resp.StatusCode < 300
 }
 
-func (c *HttpClient) doRequest(method string, path string, body []byte) (*http.Response, error) {
-	req, err := http.NewRequest(method, c.url(path), bytes.NewBuffer(body))
+func (c *HttpClient) doRequestWithRetry(ctx context.Context, op operation, request interface{}, body []byte, encoding string) (*http.Response, error) {
+	retry := c.config.Retry
+	maxAttempts := retry.maxAttempts()
+
+	for attempt := 1; ; attempt++ {
+		if err := c.waitRateLimit(ctx, op.path); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := c.doRequest(ctx, op, attempt, request, body, encoding)
+		c.recordRequest(op, resp, err, time.Since(start))
+		c.logResponse(ctx, op, attempt, resp, err, time.Since(start))
+		if err == nil && c.isSuccess(resp) {
+			return resp, nil
+		}
+		if attempt >= maxAttempts || ctx.Err() != nil || !retry.shouldRetry(op.idempotent, resp, err) {
+			return resp, err
+		}
+
+		c.recordRetry(op)
+		delay := retry.delay(attempt, resp)
+		c.logRetry(ctx, op, attempt, delay)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *HttpClient) waitRateLimit(ctx context.Context, path string) error {
+	var limiters []RateLimiter
+	if c.config.RateLimiter != nil {
+		limiters = append(limiters, c.config.RateLimiter)
+	}
+	if limiter, ok := c.config.EndpointRateLimiters[path]; ok && limiter != nil {
+		limiters = append(limiters, limiter)
+	}
+	return waitAll(ctx, limiters...)
+}
+
+func (c *HttpClient) doRequest(ctx context.Context, op operation, attempt int, request interface{}, body []byte, encoding string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(op.path), bytes.NewBuffer(body))
 	if err != nil {
 		return nil, NewClientErrorWithCause("failed to create HTTP request", err)
 	}
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Add(headerContentEncoding, encoding)
+	}
 	req.Header.Add("User-Agent", fmt.Sprintf("CodeMakerSdkGo/%s", Version))
 	req.Header.Add(headerAuthorization, fmt.Sprintf("Bearer %s", c.config.ApiKey))
+	if c.config.Tracer != nil {
+		c.config.Tracer.Inject(ctx, req.Header)
+	}
+	c.logRequest(ctx, op, attempt, req, request)
 
-	resp, err := c.client.Do(req)
-	return resp, err
+	return c.handler(&Call{
+		Operation:   op.name,
+		Request:     request,
+		HttpRequest: req,
+	})
+}
+
+func (c *HttpClient) send(call *Call) (*http.Response, error) {
+	return c.client.Do(call.HttpRequest)
 }
 
 func (c *HttpClient) handleResponse(resp *http.Response, val interface{}) (interface{}, error) {
@@ -164,6 +281,9 @@ func (c *HttpClient) handleResponse(resp *http.Response, val interface{}) (inter
 	if err != nil {
 		return nil, NewClientErrorWithCause("failed to read HTTP response", err)
 	}
+	if len(bytes.TrimSpace(reader)) == 0 {
+		return val, nil
+	}
 
 	err = json.Unmarshal(reader, val)
 	if err != nil {
@@ -177,24 +297,27 @@ func (c *HttpClient) handleError(resp *http.Response) error {
 	if id, ok := resp.Header[headerRequestId]; ok {
 		requestId = id[0]
 	}
-	errorCode := c.tryUnmarshallError(resp)
-
-	return NewClientError(
-		fmt.Sprintf("(%s) request failed %d %s", requestId, resp.StatusCode, errorCode))
-}
 
-func (c *HttpClient) tryUnmarshallError(resp *http.Response) string {
-	reader, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return ""
+	body, _ := io.ReadAll(resp.Body)
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestId:  requestId,
+		Body:       string(body),
 	}
+	if result := c.tryUnmarshallError(body); result != nil {
+		apiErr.Code = result.Code
+		apiErr.Message = result.Message
+	}
+	return apiErr
+}
 
+func (c *HttpClient) tryUnmarshallError(body []byte) *Error {
 	result := &Error{}
-	err = json.Unmarshal(reader, result)
+	err := json.Unmarshal(body, result)
 	if err != nil {
-		return ""
+		return nil
 	}
-	return result.Code
+	return result
 }
 
 func (c *HttpClient) endpoint() string {
@@ -3,10 +3,13 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func client(endpoint string) Client {
@@ -89,7 +92,7 @@ func TestClient(t *testing.T) {
 
 		client := client(ts.URL)
 
-		languages := []string{LanguageJavaScript, LanguageTypeScript, LanguageJava, LanguageGo, LanguageKotlin}
+		languages := []Language{LanguageJavaScript, LanguageTypeScript, LanguageJava, LanguageGo, LanguageKotlin}
 		for _, language := range languages {
 			got, err := client.CreateProcess(&CreateProcessRequest{
 				Process: Process{
@@ -346,7 +349,7 @@ func TestClient(t *testing.T) {
 		if err == nil {
 			t.Fatalf("Error was expected")
 		}
-		if err.Error() != "(123456789) request failed 400 BAD_REQUEST" {
+		if err.Error() != "(123456789) request failed 400 BAD_REQUEST: Bad request." {
 			t.Fatalf("Incorrect error message %v", err)
 		}
 	})
@@ -374,7 +377,7 @@ func TestClient(t *testing.T) {
 		if err == nil {
 			t.Fatalf("Error was expected")
 		}
-		if err.Error() != "(123456789) request failed 401 UNAUTHORIZED" {
+		if err.Error() != "(123456789) request failed 401 UNAUTHORIZED: Unauthorized." {
 			t.Fatalf("Incorrect error message %v", err)
 		}
 	})
@@ -402,7 +405,7 @@ func TestClient(t *testing.T) {
 		if err == nil {
 			t.Fatalf("Error was expected")
 		}
-		if err.Error() != "(123456789) request failed 500 SERVICE_ERROR" {
+		if err.Error() != "(123456789) request failed 500 SERVICE_ERROR: Unknown service error" {
 			t.Fatalf("Incorrect error message %v", err)
 		}
 	})
@@ -449,7 +452,7 @@ func TestClient(t *testing.T) {
 		if err == nil {
 			t.Fatalf("Error was expected")
 		}
-		if err.Error() != "(123456789) request failed 500 SERVICE_ERROR" {
+		if err.Error() != "(123456789) request failed 500 SERVICE_ERROR: Unknown service error" {
 			t.Fatalf("Incorrect error message %v", err)
 		}
 	})
@@ -496,8 +499,133 @@ func TestClient(t *testing.T) {
 		if err == nil {
 			t.Fatalf("Error was expected")
 		}
-		if err.Error() != "(123456789) request failed 500 SERVICE_ERROR" {
+		if err.Error() != "(123456789) request failed 500 SERVICE_ERROR: Unknown service error" {
 			t.Fatalf("Incorrect error message %v", err)
 		}
 	})
+
+	t.Run("CreateProcessWithContext request is successful", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintln(w, `{"id": "id"}`)
+		}))
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		got, err := client.CreateProcessWithContext(context.Background(), &CreateProcessRequest{
+			Process: Process{
+				Mode:     ModeDocument,
+				Language: LanguageJava,
+				Input: Input{
+					Source: "",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		if got == nil {
+			t.Fatalf("Response was expect not to be nil")
+		}
+		if got.Id != "id" {
+			t.Fatalf("Response id was incorrect got %s", got.Id)
+		}
+	})
+
+	t.Run("CreateProcessWithContext results in cancellation error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintln(w, `{"id": "id"}`)
+		}))
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.CreateProcessWithContext(ctx, &CreateProcessRequest{})
+
+		if err == nil {
+			t.Fatalf("Error was expected")
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Error was expected to be context.Canceled got %v", err)
+		}
+	})
+
+	t.Run("GetProcessStatusWithContext results in deadline exceeded error", func(t *testing.T) {
+		done := make(chan struct{})
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-done:
+			}
+		}))
+		defer ts.Close()
+		defer close(done)
+
+		client := client(ts.URL)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetProcessStatusWithContext(ctx, &GetProcessStatusRequest{
+			Id: "id",
+		})
+
+		if err == nil {
+			t.Fatalf("Error was expected")
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Error was expected to be context.DeadlineExceeded got %v", err)
+		}
+	})
+
+	t.Run("GetProcessOutputWithContext results in transport error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		ts.Close()
+
+		client := client(ts.URL)
+
+		_, err := client.GetProcessOutputWithContext(context.Background(), &GetProcessOutputRequest{
+			Id: "id",
+		})
+
+		if err == nil {
+			t.Fatalf("Error was expected")
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Error was not expected to be a context error got %v", err)
+		}
+		if err.Error() != "failed to make HTTP request" {
+			t.Fatalf("Incorrect error message %v", err)
+		}
+	})
+
+	t.Run("CancelProcess request is successful", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/process/cancel" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		got, err := client.CancelProcess(&CancelProcessRequest{
+			Id: "id",
+		})
+		if err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		if got == nil {
+			t.Fatalf("Response was expect not to be nil")
+		}
+	})
 }
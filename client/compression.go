@@ -0,0 +1,41 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+const (
+	// DefaultCompressionThreshold is the body size in bytes above which requests are compressed by default.
+	DefaultCompressionThreshold = 8 * 1024
+
+	headerContentEncoding = "Content-Encoding"
+	encodingGzip          = "gzip"
+)
+
+// compressBody gzip compresses the request body when compression is enabled and the body exceeds the threshold.
+// It returns the body to send and the content encoding, empty when the body is sent as is.
+func (c Config) compressBody(body []byte) ([]byte, string, error) {
+	if !c.CompressRequests || len(body) <= c.compressionThreshold() {
+		return body, "", nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, "", NewClientErrorWithCause("failed to compress HTTP request", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", NewClientErrorWithCause("failed to compress HTTP request", err)
+	}
+	return buf.Bytes(), encodingGzip, nil
+}
+
+func (c Config) compressionThreshold() int {
+	if c.CompressionThreshold != nil && *c.CompressionThreshold >= 0 {
+		return *c.CompressionThreshold
+	}
+	return DefaultCompressionThreshold
+}
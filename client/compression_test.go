@@ -0,0 +1,120 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func compressionServer(encodings *[]string, sources *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gzipReader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			reader = gzipReader
+		}
+
+		var request CreateProcessRequest
+		if err := json.NewDecoder(reader).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*encodings = append(*encodings, r.Header.Get("Content-Encoding"))
+		*sources = append(*sources, request.Process.Input.Source)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "id"}`))
+	}))
+}
+
+func TestCompression(t *testing.T) {
+
+	t.Run("Requests above the threshold are compressed", func(t *testing.T) {
+		var encodings, sources []string
+		ts := compressionServer(&encodings, &sources)
+		defer ts.Close()
+
+		endpoint := ts.URL
+		threshold := 1024
+		c := NewClient(Config{
+			ApiKey:               "ABCDE-GHIJK-LMNOP-QRSTU-1",
+			Endpoint:             &endpoint,
+			CompressRequests:     true,
+			CompressionThreshold: &threshold,
+		})
+
+		small := "package main"
+		large := strings.Repeat("package main\n", 100)
+		for _, source := range []string{small, large} {
+			if _, err := c.CreateProcess(&CreateProcessRequest{Process: Process{Input: Input{Source: source}}}); err != nil {
+				t.Fatalf("Request failed with an error %v", err)
+			}
+		}
+
+		if len(encodings) != 2 || encodings[0] != "" || encodings[1] != "gzip" {
+			t.Fatalf("Encodings were incorrect got %v", encodings)
+		}
+		if sources[0] != small || sources[1] != large {
+			t.Fatalf("Sources were incorrect got %v", sources)
+		}
+	})
+
+	t.Run("Requests are not compressed by default", func(t *testing.T) {
+		var encodings, sources []string
+		ts := compressionServer(&encodings, &sources)
+		defer ts.Close()
+
+		source := strings.Repeat("package main\n", DefaultCompressionThreshold)
+		if _, err := client(ts.URL).CreateProcess(&CreateProcessRequest{Process: Process{Input: Input{Source: source}}}); err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		if len(encodings) != 1 || encodings[0] != "" {
+			t.Fatalf("Encodings were incorrect got %v", encodings)
+		}
+	})
+	t.Run("Retried requests are sent compressed", func(t *testing.T) {
+		var encodings, sources []string
+		inner := compressionServer(&encodings, &sources)
+		defer inner.Close()
+
+		var attempts int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			inner.Config.Handler.ServeHTTP(w, r)
+		}))
+		defer ts.Close()
+
+		endpoint := ts.URL
+		threshold := 0
+		delay := time.Millisecond
+		c := NewClient(Config{
+			ApiKey:               "ABCDE-GHIJK-LMNOP-QRSTU-1",
+			Endpoint:             &endpoint,
+			Retry:                &RetryConfig{BaseDelay: &delay},
+			CompressRequests:     true,
+			CompressionThreshold: &threshold,
+		})
+
+		if _, err := c.GetProcessStatus(&GetProcessStatusRequest{Id: "id"}); err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+		if attempts != 2 || len(encodings) != 1 || encodings[0] != "gzip" {
+			t.Fatalf("Retried request was incorrect got %d attempts and %v", attempts, encodings)
+		}
+	})
+}
@@ -2,11 +2,275 @@
 
 package client
 
-import "time"
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	defaultProfile    = "default"
+	defaultConfigDir  = ".codemaker"
+	defaultConfigFile = "config"
+
+	envApiKey            = "CODEMAKER_API_KEY"
+	envEndpoint          = "CODEMAKER_ENDPOINT"
+	envConnectionTimeout = "CODEMAKER_CONNECTION_TIMEOUT"
+	envRequestTimeout    = "CODEMAKER_REQUEST_TIMEOUT"
+	envProfile           = "CODEMAKER_PROFILE"
+	envConfigFile        = "CODEMAKER_CONFIG_FILE"
+
+	fileApiKey            = "api_key"
+	fileEndpoint          = "endpoint"
+	fileConnectionTimeout = "connection_timeout"
+	fileRequestTimeout    = "request_timeout"
+)
+
+const (
+	ConfigKeyApiKey            = "ApiKey"
+	ConfigKeyEndpoint          = "Endpoint"
+	ConfigKeyConnectionTimeout = "ConnectionTimeout"
+	ConfigKeyRequestTimeout    = "RequestTimeout"
+)
+
+type ConfigSource string
+
+const (
+	ConfigSourceDefault     ConfigSource = "default"
+	ConfigSourceFile        ConfigSource = "file"
+	ConfigSourceEnvironment ConfigSource = "environment"
+	ConfigSourceExplicit    ConfigSource = "explicit"
+)
 
 type Config struct {
 	ApiKey            string
 	Endpoint          *string
 	ConnectionTimeout *time.Duration
 	RequestTimeout    *time.Duration
+	Retry             *RetryConfig
+	// RateLimiter is waited on before every request.
+	RateLimiter RateLimiter
+	// EndpointRateLimiters are waited on before requests to the matching path, e.g. "/process/status".
+	EndpointRateLimiters map[string]RateLimiter
+	// Middlewares wrap every HTTP request, the first middleware being the outermost.
+	Middlewares []Middleware
+	// Tracer, when set, receives a span for every call and for the lifecycle of every created process.
+	Tracer Tracer
+	// Metrics, when set, receives the request and process measurements, see NewPrometheusMetrics.
+	Metrics Metrics
+	// Logger, when set, receives the request, retry, process status and error logs. The API key is never logged.
+	Logger *slog.Logger
+	// LogSource controls how the process input source is logged, defaults to LogSourceOmit.
+	LogSource LogSource
+	// LogSourceLength is the number of characters logged with LogSourceTruncate.
+	LogSourceLength *int
+	// ValidateRequests validates CreateProcess requests before sending them, see CreateProcessRequest.Validate.
+	ValidateRequests bool
+	// Capabilities are used by the request validation, defaults to DefaultCapabilities.
+	Capabilities *CapabilityRegistry
+	// CompressRequests gzip compresses the request bodies larger than CompressionThreshold.
+	CompressRequests bool
+	// CompressionThreshold is the request body size in bytes above which the body is compressed, defaults to
+	// DefaultCompressionThreshold.
+	CompressionThreshold *int
+}
+
+type LoadConfigOptions struct {
+	// Profile is the config file profile to use, defaults to CODEMAKER_PROFILE or "default".
+	Profile string
+	// File is the config file path, defaults to CODEMAKER_CONFIG_FILE or ~/.codemaker/config.
+	File string
+	// Explicit values take precedence over all other sources.
+	Explicit Config
+}
+
+type LoadedConfig struct {
+	Config Config
+	// Sources maps each ConfigKey to the source its value came from.
+	Sources map[string]ConfigSource
+}
+
+// LoadConfig layers the defaults, the config file profile, the environment variables and the explicit values, in
+// that order of precedence.
+//
+// The config file contains profiles in the following format:
+//
+//	[default]
+//	api_key = ABCDE-GHIJK-LMNOP-QRSTU-1
+//	endpoint = https://api.codemaker.ai
+//	connection_timeout = 5s
+//	request_timeout = 50s
+func LoadConfig(options LoadConfigOptions) (*LoadedConfig, error) {
+	loaded := &LoadedConfig{
+		Config:  options.Explicit,
+		Sources: map[string]ConfigSource{},
+	}
+
+	values := map[string]string{
+		ConfigKeyEndpoint:          endpointUrl,
+		ConfigKeyConnectionTimeout: defaultConnectionTimeout.String(),
+		ConfigKeyRequestTimeout:    defaultRequestTimeout.String(),
+	}
+	for key := range values {
+		loaded.Sources[key] = ConfigSourceDefault
+	}
+
+	profile, err := loadProfile(options)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range profile {
+		values[key] = value
+		loaded.Sources[key] = ConfigSourceFile
+	}
+
+	for key, env := range map[string]string{
+		ConfigKeyApiKey:            envApiKey,
+		ConfigKeyEndpoint:          envEndpoint,
+		ConfigKeyConnectionTimeout: envConnectionTimeout,
+		ConfigKeyRequestTimeout:    envRequestTimeout,
+	} {
+		if value, ok := os.LookupEnv(env); ok && value != "" {
+			values[key] = value
+			loaded.Sources[key] = ConfigSourceEnvironment
+		}
+	}
+
+	if err := loaded.apply(values); err != nil {
+		return nil, err
+	}
+	return loaded, nil
+}
+
+func (l *LoadedConfig) apply(values map[string]string) error {
+	explicit := l.Config
+
+	if explicit.ApiKey != "" {
+		l.Sources[ConfigKeyApiKey] = ConfigSourceExplicit
+	} else if value, ok := values[ConfigKeyApiKey]; ok {
+		l.Config.ApiKey = value
+	}
+
+	if explicit.Endpoint != nil {
+		l.Sources[ConfigKeyEndpoint] = ConfigSourceExplicit
+	} else {
+		endpoint := values[ConfigKeyEndpoint]
+		l.Config.Endpoint = &endpoint
+	}
+
+	if explicit.ConnectionTimeout != nil {
+		l.Sources[ConfigKeyConnectionTimeout] = ConfigSourceExplicit
+	} else {
+		timeout, err := parseTimeout(ConfigKeyConnectionTimeout, values[ConfigKeyConnectionTimeout])
+		if err != nil {
+			return err
+		}
+		l.Config.ConnectionTimeout = &timeout
+	}
+
+	if explicit.RequestTimeout != nil {
+		l.Sources[ConfigKeyRequestTimeout] = ConfigSourceExplicit
+	} else {
+		timeout, err := parseTimeout(ConfigKeyRequestTimeout, values[ConfigKeyRequestTimeout])
+		if err != nil {
+			return err
+		}
+		l.Config.RequestTimeout = &timeout
+	}
+	return nil
+}
+
+func parseTimeout(key string, value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, NewClientErrorWithCause(fmt.Sprintf("invalid %s value %q", key, value), err)
+	}
+	return timeout, nil
+}
+
+func loadProfile(options LoadConfigOptions) (map[string]string, error) {
+	name, nameSet := options.Profile, options.Profile != ""
+	if !nameSet {
+		name, nameSet = os.LookupEnv(envProfile)
+	}
+	if name == "" {
+		name, nameSet = defaultProfile, false
+	}
+
+	path, pathSet := options.File, options.File != ""
+	if !pathSet {
+		path, pathSet = os.LookupEnv(envConfigFile)
+	}
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		path, pathSet = filepath.Join(home, defaultConfigDir, defaultConfigFile), false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !pathSet && !nameSet {
+			return nil, nil
+		}
+		return nil, NewClientErrorWithCause("failed to open config file", err)
+	}
+	defer file.Close()
+
+	profiles, err := parseProfiles(file)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, ok := profiles[name]
+	if !ok && nameSet {
+		return nil, NewClientError(fmt.Sprintf("profile %s not found in config file %s", name, path))
+	}
+	return profile, nil
+}
+
+func parseProfiles(reader io.Reader) (map[string]map[string]string, error) {
+	keys := map[string]string{
+		fileApiKey:            ConfigKeyApiKey,
+		fileEndpoint:          ConfigKeyEndpoint,
+		fileConnectionTimeout: ConfigKeyConnectionTimeout,
+		fileRequestTimeout:    ConfigKeyRequestTimeout,
+	}
+
+	profiles := map[string]map[string]string{}
+	var profile map[string]string
+
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			profile = map[string]string{}
+			profiles[name] = profile
+			continue
+		}
+
+		name, value, ok := strings.Cut(text, "=")
+		if !ok || profile == nil {
+			return nil, NewClientError(fmt.Sprintf("invalid config file line %d", line))
+		}
+		if key, ok := keys[strings.TrimSpace(name)]; ok {
+			profile[key] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewClientErrorWithCause("failed to read config file", err)
+	}
+	return profiles, nil
 }
@@ -0,0 +1,120 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func configFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config file %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+
+	t.Run("LoadConfig uses defaults", func(t *testing.T) {
+		t.Setenv(envApiKey, "")
+		t.Setenv(envEndpoint, "")
+		t.Setenv(envProfile, "")
+		t.Setenv(envConfigFile, filepath.Join(t.TempDir(), "missing"))
+
+		_, err := LoadConfig(LoadConfigOptions{})
+		if err == nil {
+			t.Fatalf("Error was expected for missing explicit config file")
+		}
+
+		t.Setenv(envConfigFile, "")
+		t.Setenv("HOME", t.TempDir())
+
+		got, err := LoadConfig(LoadConfigOptions{})
+		if err != nil {
+			t.Fatalf("LoadConfig failed with an error %v", err)
+		}
+
+		if *got.Config.Endpoint != endpointUrl {
+			t.Fatalf("Endpoint was incorrect got %s", *got.Config.Endpoint)
+		}
+		if *got.Config.RequestTimeout != defaultRequestTimeout {
+			t.Fatalf("Request timeout was incorrect got %v", *got.Config.RequestTimeout)
+		}
+		if got.Sources[ConfigKeyEndpoint] != ConfigSourceDefault {
+			t.Fatalf("Endpoint source was incorrect got %s", got.Sources[ConfigKeyEndpoint])
+		}
+	})
+
+	t.Run("LoadConfig layers file, environment and explicit values", func(t *testing.T) {
+		path := configFile(t, `
+# CodeMaker config
+[default]
+api_key = default-key
+endpoint = https://default.codemaker.ai
+
+[staging]
+api_key = staging-key
+endpoint = https://staging.codemaker.ai
+connection_timeout = 2s
+request_timeout = 20s
+`)
+		t.Setenv(envApiKey, "")
+		t.Setenv(envEndpoint, "https://env.codemaker.ai")
+		t.Setenv(envConnectionTimeout, "")
+		t.Setenv(envRequestTimeout, "")
+		t.Setenv(envProfile, "")
+
+		requestTimeout := 30 * time.Second
+		got, err := LoadConfig(LoadConfigOptions{
+			Profile: "staging",
+			File:    path,
+			Explicit: Config{
+				RequestTimeout: &requestTimeout,
+			},
+		})
+		if err != nil {
+			t.Fatalf("LoadConfig failed with an error %v", err)
+		}
+
+		if got.Config.ApiKey != "staging-key" || got.Sources[ConfigKeyApiKey] != ConfigSourceFile {
+			t.Fatalf("Api key was incorrect got %s from %s", got.Config.ApiKey, got.Sources[ConfigKeyApiKey])
+		}
+		if *got.Config.Endpoint != "https://env.codemaker.ai" || got.Sources[ConfigKeyEndpoint] != ConfigSourceEnvironment {
+			t.Fatalf("Endpoint was incorrect got %s from %s", *got.Config.Endpoint, got.Sources[ConfigKeyEndpoint])
+		}
+		if *got.Config.ConnectionTimeout != 2*time.Second {
+			t.Fatalf("Connection timeout was incorrect got %v", *got.Config.ConnectionTimeout)
+		}
+		if *got.Config.RequestTimeout != requestTimeout || got.Sources[ConfigKeyRequestTimeout] != ConfigSourceExplicit {
+			t.Fatalf("Request timeout was incorrect got %v from %s", *got.Config.RequestTimeout, got.Sources[ConfigKeyRequestTimeout])
+		}
+	})
+
+	t.Run("LoadConfig results in error for missing profile", func(t *testing.T) {
+		path := configFile(t, "[default]\napi_key = key\n")
+
+		_, err := LoadConfig(LoadConfigOptions{
+			Profile: "missing",
+			File:    path,
+		})
+		if err == nil {
+			t.Fatalf("Error was expected")
+		}
+	})
+
+	t.Run("LoadConfig results in error for invalid timeout", func(t *testing.T) {
+		t.Setenv(envConfigFile, "")
+		t.Setenv(envProfile, "")
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv(envRequestTimeout, "soon")
+
+		_, err := LoadConfig(LoadConfigOptions{})
+		if err == nil {
+			t.Fatalf("Error was expected")
+		}
+	})
+}
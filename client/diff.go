@@ -0,0 +1,323 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultDiffContext = 3
+	defaultDiffFrom    = "input"
+	defaultDiffTo      = "output"
+)
+
+type DiffKind byte
+
+const (
+	DiffEqual  DiffKind = ' '
+	DiffDelete DiffKind = '-'
+	DiffInsert DiffKind = '+'
+)
+
+type DiffConfig struct {
+	// Context is the number of unchanged lines around each change, defaults to 3.
+	Context *int
+	// IgnoreWhitespace compares the lines ignoring all whitespace.
+	IgnoreWhitespace bool
+	// FromName and ToName are the file names in the unified diff header.
+	FromName string
+	ToName   string
+}
+
+type DiffLine struct {
+	Kind DiffKind
+	// Text is the line without its line terminator.
+	Text string
+	// NoNewline is set for the last line of a source that does not end with a newline.
+	NoNewline bool
+}
+
+type Hunk struct {
+	FromLine  int
+	FromCount int
+	ToLine    int
+	ToCount   int
+	Lines     []DiffLine
+}
+
+type Diff struct {
+	FromName string
+	ToName   string
+	Hunks    []Hunk
+}
+
+// DiffSources computes the differences between the submitted Input.Source and the returned Output.Source.
+func DiffSources(input string, output string, config DiffConfig) *Diff {
+	context := defaultDiffContext
+	if config.Context != nil && *config.Context >= 0 {
+		context = *config.Context
+	}
+
+	diff := &Diff{
+		FromName: config.FromName,
+		ToName:   config.ToName,
+	}
+	if diff.FromName == "" {
+		diff.FromName = defaultDiffFrom
+	}
+	if diff.ToName == "" {
+		diff.ToName = defaultDiffTo
+	}
+
+	from, to := splitLines(input), splitLines(output)
+	key := func(line diffSourceLine) string {
+		if config.IgnoreWhitespace {
+			return removeWhitespace(line.text)
+		}
+		if line.noNewline {
+			return line.text
+		}
+		return line.text + "\n"
+	}
+
+	ops := myers(from, to, key)
+	diff.Hunks = hunks(ops, from, to, context)
+	return diff
+}
+
+// Empty reports whether the sources are equal.
+func (d *Diff) Empty() bool {
+	return len(d.Hunks) == 0
+}
+
+// Unified formats the diff in the unified diff format. Equal sources result in an empty string.
+func (d *Diff) Unified() string {
+	if d.Empty() {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", d.FromName, d.ToName)
+	for _, hunk := range d.Hunks {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(hunk.FromLine, hunk.FromCount), hunkRange(hunk.ToLine, hunk.ToCount))
+		for _, line := range hunk.Lines {
+			sb.WriteByte(byte(line.Kind))
+			sb.WriteString(line.Text)
+			sb.WriteByte('\n')
+			if line.NoNewline {
+				sb.WriteString("\\ No newline at end of file\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+func hunkRange(line int, count int) string {
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+type diffSourceLine struct {
+	text      string
+	noNewline bool
+}
+
+type diffOp struct {
+	kind DiffKind
+	from int
+	to   int
+}
+
+func splitLines(source string) []diffSourceLine {
+	if source == "" {
+		return nil
+	}
+
+	parts := strings.SplitAfter(source, "\n")
+	if parts[len(parts)-1] == "" {
+		parts = parts[:len(parts)-1]
+	}
+
+	lines := make([]diffSourceLine, len(parts))
+	for i, part := range parts {
+		text := strings.TrimSuffix(part, "\n")
+		lines[i] = diffSourceLine{
+			text:      text,
+			noNewline: text == part,
+		}
+	}
+	return lines
+}
+
+func removeWhitespace(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// myers computes the shortest edit script between the lines using the Myers O(ND) algorithm.
+func myers(from []diffSourceLine, to []diffSourceLine, key func(diffSourceLine) string) []diffOp {
+	n, m := len(from), len(to)
+	fromKeys := make([]string, n)
+	for i, line := range from {
+		fromKeys[i] = key(line)
+	}
+	toKeys := make([]string, m)
+	for i, line := range to {
+		toKeys[i] = key(line)
+	}
+
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && fromKeys[x] == toKeys[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(trace [][]int, n int, m int) []diffOp {
+	var ops []diffOp
+	x, y := n, m
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int {
+			return v[k+d]
+		}
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{kind: DiffEqual, from: x, to: y})
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: DiffInsert, from: x, to: prevY})
+			} else {
+				ops = append(ops, diffOp{kind: DiffDelete, from: prevX, to: y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+func hunks(ops []diffOp, from []diffSourceLine, to []diffSourceLine, context int) []Hunk {
+	var result []Hunk
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == DiffEqual {
+			i++
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind == DiffEqual {
+				continue
+			}
+			// Changes separated by at most twice the context are merged into one hunk, like GNU diff does.
+			if j-end-1 > 2*context {
+				break
+			}
+			end = j
+		}
+		end += context + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		result = append(result, hunk(ops[start:end], from, to))
+		i = end
+	}
+	return result
+}
+
+func hunk(ops []diffOp, from []diffSourceLine, to []diffSourceLine) Hunk {
+	h := Hunk{
+		FromLine: ops[0].from,
+		ToLine:   ops[0].to,
+	}
+
+	for _, op := range ops {
+		var line diffSourceLine
+		switch op.kind {
+		case DiffEqual:
+			line = from[op.from]
+			h.FromCount++
+			h.ToCount++
+		case DiffDelete:
+			line = from[op.from]
+			h.FromCount++
+		case DiffInsert:
+			line = to[op.to]
+			h.ToCount++
+		}
+		h.Lines = append(h.Lines, DiffLine{
+			Kind:      op.kind,
+			Text:      line.text,
+			NoNewline: line.noNewline,
+		})
+	}
+
+	if h.FromCount > 0 {
+		h.FromLine++
+	}
+	if h.ToCount > 0 {
+		h.ToLine++
+	}
+	return h
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import "testing"
+
+func TestDiffSources(t *testing.T) {
+
+	t.Run("Equal sources result in empty diff", func(t *testing.T) {
+		got := DiffSources("a\nb\n", "a\nb\n", DiffConfig{})
+
+		if !got.Empty() {
+			t.Fatalf("Diff was expected to be empty got %v", got.Hunks)
+		}
+		if got.Unified() != "" {
+			t.Fatalf("Unified diff was expected to be empty got %s", got.Unified())
+		}
+	})
+
+	t.Run("Unified diff is computed", func(t *testing.T) {
+		input := "package main\n\nfunc main() {\n}\n"
+		output := "package main\n\n// main is the entry point.\nfunc main() {\n}\n"
+
+		got := DiffSources(input, output, DiffConfig{
+			FromName: "a/main.go",
+			ToName:   "b/main.go",
+		})
+
+		expected := "--- a/main.go\n" +
+			"+++ b/main.go\n" +
+			"@@ -1,4 +1,5 @@\n" +
+			" package main\n" +
+			" \n" +
+			"+// main is the entry point.\n" +
+			" func main() {\n" +
+			" }\n"
+		if got.Unified() != expected {
+			t.Fatalf("Unified diff was incorrect got\n%s", got.Unified())
+		}
+	})
+
+	t.Run("Distant changes result in separate hunks", func(t *testing.T) {
+		input := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+		output := "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"
+		context := 1
+
+		got := DiffSources(input, output, DiffConfig{Context: &context})
+
+		if len(got.Hunks) != 2 {
+			t.Fatalf("Hunks count was incorrect got %d", len(got.Hunks))
+		}
+		first, second := got.Hunks[0], got.Hunks[1]
+		if first.FromLine != 1 || first.FromCount != 2 || first.ToLine != 1 || first.ToCount != 2 {
+			t.Fatalf("First hunk was incorrect got %+v", first)
+		}
+		if second.FromLine != 9 || second.FromCount != 2 || second.ToLine != 9 || second.ToCount != 2 {
+			t.Fatalf("Second hunk was incorrect got %+v", second)
+		}
+	})
+
+	t.Run("Changes separated by twice the context result in one hunk", func(t *testing.T) {
+		context := 1
+
+		got := DiffSources("a\n1\n2\nb\n", "A\n1\n2\nB\n", DiffConfig{Context: &context})
+
+		if len(got.Hunks) != 1 {
+			t.Fatalf("Hunks count was incorrect got %d", len(got.Hunks))
+		}
+		if h := got.Hunks[0]; h.FromLine != 1 || h.FromCount != 4 || h.ToLine != 1 || h.ToCount != 4 {
+			t.Fatalf("Hunk was incorrect got %+v", h)
+		}
+	})
+
+	t.Run("Whitespace changes are ignored", func(t *testing.T) {
+		got := DiffSources("func main() {\n\treturn\n}\n", "func main()  {\n    return\n}", DiffConfig{
+			IgnoreWhitespace: true,
+		})
+
+		if !got.Empty() {
+			t.Fatalf("Diff was expected to be empty got\n%s", got.Unified())
+		}
+	})
+
+	t.Run("Missing newline at end of file is reported", func(t *testing.T) {
+		got := DiffSources("a\n", "a", DiffConfig{})
+
+		expected := "--- input\n" +
+			"+++ output\n" +
+			"@@ -1 +1 @@\n" +
+			"-a\n" +
+			"+a\n" +
+			"\\ No newline at end of file\n"
+		if got.Unified() != expected {
+			t.Fatalf("Unified diff was incorrect got\n%s", got.Unified())
+		}
+	})
+
+	t.Run("Diff of empty input", func(t *testing.T) {
+		got := DiffSources("", "a\n", DiffConfig{})
+
+		expected := "--- input\n" +
+			"+++ output\n" +
+			"@@ -0,0 +1 @@\n" +
+			"+a\n"
+		if got.Unified() != expected {
+			t.Fatalf("Unified diff was incorrect got\n%s", got.Unified())
+		}
+	})
+}
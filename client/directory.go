@@ -0,0 +1,193 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const defaultMinLanguageConfidence = 0.5
+
+type DirectoryConfig struct {
+	Mode    Mode
+	Options *Options
+	// MinLanguageConfidence is the confidence of the detected language required to process a file.
+	MinLanguageConfidence float64
+	// OutputDir receives the results in a tree mirroring the processed directory. When empty, the results are
+	// written back to the source files.
+	OutputDir string
+	// StateDir keeps the backups of the files written back, defaults to DefaultStateDir in the processed directory.
+	StateDir string
+	// IncludeHidden processes the hidden files and directories, such as .git, which are skipped by default.
+	IncludeHidden bool
+	Batch         BatchConfig
+}
+
+type DirectorySummary struct {
+	// Processed are the paths, relative to the processed directory, of the successfully processed files.
+	Processed []string
+	// Skipped are the paths of the unsupported or binary files.
+	Skipped []string
+	// Failed maps the paths of the failed files to their errors.
+	Failed map[string]error
+}
+
+// ProcessDirectory walks the directory tree, detects the language of every file and processes the supported ones
+// in the configured mode.
+func ProcessDirectory(ctx context.Context, client Client, root string, config DirectoryConfig) (*DirectorySummary, error) {
+	files, err := walkDirectory(root, config.IncludeHidden, config.stateDir(root))
+	if err != nil {
+		return nil, NewClientErrorWithCause("failed to walk directory", err)
+	}
+
+	summary := &DirectorySummary{
+		Failed: map[string]error{},
+	}
+
+	var writeBack *WriteBack
+	if config.OutputDir == "" {
+		writeBack, err = NewWriteBack(config.stateDir(root))
+		if err != nil {
+			return nil, err
+		}
+		defer writeBack.Close()
+	}
+
+	var mu sync.Mutex
+	sources := map[string][]byte{}
+
+	jobs := make(chan BatchJob)
+	skipped := make(chan string, len(files))
+	failed := make(chan fileError, len(files))
+	go func() {
+		defer close(jobs)
+		defer close(skipped)
+		defer close(failed)
+
+		for _, file := range files {
+			source, err := os.ReadFile(filepath.Join(root, file))
+			if err != nil {
+				failed <- fileError{path: file, err: err}
+				continue
+			}
+
+			detection := DetectLanguage(file, source)
+			if !detection.Supported() || detection.Confidence < config.minLanguageConfidence() {
+				skipped <- file
+				continue
+			}
+
+			mu.Lock()
+			sources[file] = source
+			mu.Unlock()
+
+			jobs <- BatchJob{
+				Id: file,
+				Process: Process{
+					Mode:     config.Mode,
+					Language: detection.Language,
+					Input: Input{
+						Source: string(source),
+					},
+					Options: config.Options,
+				},
+			}
+		}
+	}()
+
+	processor := NewBatchProcessor(client, config.Batch)
+	for result := range processor.ProcessChannel(ctx, jobs) {
+		if result.Err != nil {
+			summary.Failed[result.JobId] = result.Err
+			continue
+		}
+		mu.Lock()
+		source := sources[result.JobId]
+		delete(sources, result.JobId)
+		mu.Unlock()
+
+		if err := writeDirectoryResult(writeBack, root, config.OutputDir, result.JobId, source, result.Output.Source); err != nil {
+			summary.Failed[result.JobId] = err
+			continue
+		}
+		summary.Processed = append(summary.Processed, result.JobId)
+	}
+
+	for file := range skipped {
+		summary.Skipped = append(summary.Skipped, file)
+	}
+	for f := range failed {
+		summary.Failed[f.path] = f.err
+	}
+
+	sort.Strings(summary.Processed)
+	sort.Strings(summary.Skipped)
+	return summary, nil
+}
+
+func (c DirectoryConfig) minLanguageConfidence() float64 {
+	if c.MinLanguageConfidence > 0 {
+		return c.MinLanguageConfidence
+	}
+	return defaultMinLanguageConfidence
+}
+
+func (c DirectoryConfig) stateDir(root string) string {
+	if c.StateDir != "" {
+		return c.StateDir
+	}
+	return filepath.Join(root, DefaultStateDir)
+}
+
+type fileError struct {
+	path string
+	err  error
+}
+
+func walkDirectory(root string, includeHidden bool, stateDir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() && filepath.Clean(path) == filepath.Clean(stateDir) {
+			return filepath.SkipDir
+		}
+		if path != root && !includeHidden && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	return files, err
+}
+
+func writeDirectoryResult(writeBack *WriteBack, root string, outputDir string, file string, original []byte, source string) error {
+	if outputDir == "" {
+		return writeBack.Write(filepath.Join(root, file), original, []byte(source))
+	}
+
+	target := filepath.Join(outputDir, file)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, []byte(source), 0644)
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func sourceTree(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file %v", err)
+		}
+	}
+	return root
+}
+
+func TestProcessDirectory(t *testing.T) {
+
+	files := map[string]string{
+		"main.go":          "package main",
+		"src/App.java":     "class App {}",
+		"src/lib/util.h":   "int util();",
+		"README.md":        "# Readme",
+		"assets/logo.png":  "\x89PNG\x00\x00",
+		".git/config":      "[core]",
+		"web/index.ts":     "export {}",
+		"web/vendor/x.bin": "\x00\x01\x02",
+	}
+
+	t.Run("ProcessDirectory writes results in place", func(t *testing.T) {
+		ts := processServer(StatusCompleted)
+		defer ts.Close()
+
+		root := sourceTree(t, files)
+
+		got, err := ProcessDirectory(context.Background(), client(ts.URL), root, DirectoryConfig{
+			Mode:  ModeDocument,
+			Batch: BatchConfig{Poll: pollConfig()},
+		})
+		if err != nil {
+			t.Fatalf("ProcessDirectory failed with an error %v", err)
+		}
+
+		expected := []string{"main.go", filepath.Join("src", "App.java"), filepath.Join("src", "lib", "util.h"), filepath.Join("web", "index.ts")}
+		if len(got.Processed) != len(expected) {
+			t.Fatalf("Processed files were incorrect got %v", got.Processed)
+		}
+		for _, file := range expected {
+			content, _ := os.ReadFile(filepath.Join(root, file))
+			if string(content) != "source" {
+				t.Fatalf("File %s was not updated got %s", file, content)
+			}
+		}
+		if len(got.Skipped) != 3 {
+			t.Fatalf("Skipped files were incorrect got %v", got.Skipped)
+		}
+		if len(got.Failed) != 0 {
+			t.Fatalf("Failed files were incorrect got %v", got.Failed)
+		}
+
+		if content, _ := os.ReadFile(filepath.Join(root, ".git", "config")); string(content) != "[core]" {
+			t.Fatalf("Hidden file was expected to be skipped")
+		}
+	})
+
+	t.Run("ProcessDirectory writes results into mirror tree", func(t *testing.T) {
+		ts := processServer(StatusCompleted)
+		defer ts.Close()
+
+		root := sourceTree(t, files)
+		output := t.TempDir()
+
+		got, err := ProcessDirectory(context.Background(), client(ts.URL), root, DirectoryConfig{
+			Mode:      ModeDocument,
+			OutputDir: output,
+			Batch:     BatchConfig{Poll: pollConfig()},
+		})
+		if err != nil {
+			t.Fatalf("ProcessDirectory failed with an error %v", err)
+		}
+
+		if len(got.Processed) != 4 {
+			t.Fatalf("Processed files were incorrect got %v", got.Processed)
+		}
+		if content, _ := os.ReadFile(filepath.Join(output, "src", "App.java")); string(content) != "source" {
+			t.Fatalf("Mirror file was incorrect got %s", content)
+		}
+		if content, _ := os.ReadFile(filepath.Join(root, "src", "App.java")); string(content) != "class App {}" {
+			t.Fatalf("Source file was expected not to change got %s", content)
+		}
+	})
+
+	t.Run("ProcessDirectory reports failed files", func(t *testing.T) {
+		ts := processServer(StatusFailed)
+		defer ts.Close()
+
+		root := sourceTree(t, map[string]string{"main.go": "package main"})
+
+		got, err := ProcessDirectory(context.Background(), client(ts.URL), root, DirectoryConfig{
+			Mode:  ModeDocument,
+			Batch: BatchConfig{Poll: pollConfig()},
+		})
+		if err != nil {
+			t.Fatalf("ProcessDirectory failed with an error %v", err)
+		}
+
+		if len(got.Failed) != 1 || got.Failed["main.go"] == nil {
+			t.Fatalf("Failed files were incorrect got %v", got.Failed)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Mode is the kind of processing requested. Values unknown to this version of the SDK are preserved.
+type Mode string
+
+// Language is the programming language of the processed source. Values unknown to this version of the SDK are
+// preserved.
+type Language string
+
+// Status is the status of a process. Values unknown to this version of the SDK are preserved.
+type Status string
+
+// Modify controls whether existing code is replaced. Values unknown to this version of the SDK are preserved.
+type Modify string
+
+var (
+	modes = []Mode{
+		ModeCompletion,
+		ModeCode,
+		ModeInlineCode,
+		ModeEditCode,
+		ModeDocument,
+		ModeUnitTest,
+		ModeMigrateSyntax,
+		ModeRefactorNaming,
+		ModeFixSyntax,
+	}
+
+	languages = []Language{
+		LanguageC,
+		LanguageCPP,
+		LanguageJavaScript,
+		LanguagePHP,
+		LanguageJava,
+		LanguageCSharp,
+		LanguageGo,
+		LanguageKotlin,
+		LanguageTypeScript,
+		LanguageRust,
+	}
+
+	statuses = []Status{StatusInProgress, StatusCompleted, StatusFailed, StatusTimedOut, StatusCancelled}
+
+	modifies = []Modify{ModifyNone, ModifyReplace}
+
+	languageAliases = map[string]Language{
+		"C++":    LanguageCPP,
+		"C#":     LanguageCSharp,
+		"JS":     LanguageJavaScript,
+		"TS":     LanguageTypeScript,
+		"GOLANG": LanguageGo,
+		"KT":     LanguageKotlin,
+		"RS":     LanguageRust,
+		"CS":     LanguageCSharp,
+	}
+)
+
+// Modes returns the modes known to this version of the SDK.
+func Modes() []Mode {
+	return append([]Mode(nil), modes...)
+}
+
+// Languages returns the languages known to this version of the SDK.
+func Languages() []Language {
+	return append([]Language(nil), languages...)
+}
+
+// ParseMode parses a known mode, ignoring case and accepting dashes or spaces for underscores, e.g. "unit-test".
+func ParseMode(value string) (Mode, error) {
+	return parseEnum("mode", modes, value)
+}
+
+// ParseLanguage parses a known language, ignoring case and accepting common aliases, e.g. "c++" or "golang".
+func ParseLanguage(value string) (Language, error) {
+	if language, ok := languageAliases[normalizeEnum(value)]; ok {
+		return language, nil
+	}
+	return parseEnum("language", languages, value)
+}
+
+// ParseStatus parses a known status, ignoring case and accepting dashes or spaces for underscores.
+func ParseStatus(value string) (Status, error) {
+	return parseEnum("status", statuses, value)
+}
+
+// ParseModify parses a known modify value, ignoring case.
+func ParseModify(value string) (Modify, error) {
+	return parseEnum("modify value", modifies, value)
+}
+
+func (m Mode) String() string {
+	return string(m)
+}
+
+// IsKnown returns true when the mode is known to this version of the SDK.
+func (m Mode) IsKnown() bool {
+	return contains(modes, m)
+}
+
+func (m Mode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(m))
+}
+
+func (m *Mode) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, m)
+}
+
+func (l Language) String() string {
+	return string(l)
+}
+
+// IsKnown returns true when the language is known to this version of the SDK.
+func (l Language) IsKnown() bool {
+	return contains(languages, l)
+}
+
+func (l Language) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(l))
+}
+
+func (l *Language) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, l)
+}
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// IsKnown returns true when the status is known to this version of the SDK.
+func (s Status) IsKnown() bool {
+	return contains(statuses, s)
+}
+
+// IsTerminal returns true when the process will not change its status anymore.
+func (s Status) IsTerminal() bool {
+	switch s {
+	case StatusCompleted, StatusFailed, StatusTimedOut, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (s Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+func (s *Status) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s)
+}
+
+func (m Modify) String() string {
+	return string(m)
+}
+
+// IsKnown returns true when the modify value is known to this version of the SDK.
+func (m Modify) IsKnown() bool {
+	return contains(modifies, m)
+}
+
+func (m Modify) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(m))
+}
+
+func (m *Modify) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, m)
+}
+
+func parseEnum[T ~string](name string, known []T, value string) (T, error) {
+	normalized := T(normalizeEnum(value))
+	if contains(known, normalized) {
+		return normalized, nil
+	}
+	return "", NewClientError(fmt.Sprintf("unknown %s %q", name, value))
+}
+
+func normalizeEnum(value string) string {
+	return strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToUpper(strings.TrimSpace(value)))
+}
+
+// unmarshalEnum accepts any string, so that values added to the API later do not fail the decoding.
+func unmarshalEnum[T ~string](data []byte, value *T) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*value = ""
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*value = T(s)
+	return nil
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
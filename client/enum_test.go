@@ -0,0 +1,86 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnum(t *testing.T) {
+
+	t.Run("Parse functions accept known values", func(t *testing.T) {
+		if got, err := ParseMode("unit-test"); err != nil || got != ModeUnitTest {
+			t.Fatalf("ParseMode was incorrect got %s %v", got, err)
+		}
+		if got, err := ParseLanguage("c++"); err != nil || got != LanguageCPP {
+			t.Fatalf("ParseLanguage was incorrect got %s %v", got, err)
+		}
+		if got, err := ParseLanguage("Kotlin"); err != nil || got != LanguageKotlin {
+			t.Fatalf("ParseLanguage was incorrect got %s %v", got, err)
+		}
+		if got, err := ParseStatus("timed out"); err != nil || got != StatusTimedOut {
+			t.Fatalf("ParseStatus was incorrect got %s %v", got, err)
+		}
+		if got, err := ParseModify("replace"); err != nil || got != ModifyReplace {
+			t.Fatalf("ParseModify was incorrect got %s %v", got, err)
+		}
+	})
+
+	t.Run("Parse functions reject unknown values", func(t *testing.T) {
+		if _, err := ParseMode("summarize"); err == nil {
+			t.Fatalf("ParseMode was expected to fail")
+		}
+		if _, err := ParseLanguage("COBOL"); err == nil {
+			t.Fatalf("ParseLanguage was expected to fail")
+		}
+	})
+
+	t.Run("JSON encoding is unchanged", func(t *testing.T) {
+		modify := ModifyReplace
+		data, err := json.Marshal(&Process{
+			Mode:     ModeDocument,
+			Language: LanguageGo,
+			Options: &Options{
+				Modify: &modify,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Marshal failed with an error %v", err)
+		}
+
+		expected := `{"mode":"DOCUMENT","language":"GO","input":{"source":""},"options":{"languageVersion":null,"framework":null,"modify":"REPLACE","codePath":null}}`
+		if string(data) != expected {
+			t.Fatalf("JSON was incorrect got %s", data)
+		}
+	})
+
+	t.Run("JSON decoding preserves unknown values", func(t *testing.T) {
+		var response GetProcessStatusResponse
+		if err := json.Unmarshal([]byte(`{"status": "QUEUED"}`), &response); err != nil {
+			t.Fatalf("Unmarshal failed with an error %v", err)
+		}
+		if response.Status != "QUEUED" || response.Status.IsKnown() || response.Status.IsTerminal() {
+			t.Fatalf("Status was incorrect got %s", response.Status)
+		}
+
+		var process Process
+		if err := json.Unmarshal([]byte(`{"mode": null, "language": "ZIG"}`), &process); err != nil {
+			t.Fatalf("Unmarshal failed with an error %v", err)
+		}
+		if process.Mode != "" || process.Language != "ZIG" {
+			t.Fatalf("Process was incorrect got %v", process)
+		}
+	})
+
+	t.Run("Final statuses are terminal", func(t *testing.T) {
+		for _, status := range []Status{StatusCompleted, StatusFailed, StatusTimedOut, StatusCancelled} {
+			if !status.IsTerminal() {
+				t.Fatalf("Status %s was expected to be terminal", status)
+			}
+		}
+		if StatusInProgress.IsTerminal() {
+			t.Fatalf("Status %s was expected not to be terminal", StatusInProgress)
+		}
+	})
+}
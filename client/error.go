@@ -2,6 +2,12 @@
 
 package client
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
 type ClientError interface {
 	error
 	Unwrap() error
@@ -33,3 +39,49 @@ func (e *clientError) Error() string {
 func (e *clientError) Unwrap() error {
 	return e.cause
 }
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServiceError = errors.New("service error")
+)
+
+// APIError is returned when the API responds with a non-successful HTTP status.
+type APIError struct {
+	StatusCode int
+	RequestId  string
+	Code       string
+	Message    string
+	// Body is the raw response body, kept for responses that are not JSON.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("(%s) request failed %d %s", e.RequestId, e.StatusCode, e.Code)
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	return message
+}
+
+// Unwrap returns the sentinel error matching the HTTP status, if any.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrServiceError
+	}
+	return nil
+}
@@ -3,7 +3,10 @@
 package client
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -33,4 +36,71 @@ func TestClientError(t *testing.T) {
 			t.Fatalf("Error cause is incorrt got %v", got.Unwrap())
 		}
 	})
+
+	t.Run("APIError matches sentinel errors", func(t *testing.T) {
+		cases := map[int]error{
+			http.StatusBadRequest:          ErrBadRequest,
+			http.StatusUnauthorized:        ErrUnauthorized,
+			http.StatusForbidden:           ErrForbidden,
+			http.StatusNotFound:            ErrNotFound,
+			http.StatusTooManyRequests:     ErrRateLimited,
+			http.StatusInternalServerError: ErrServiceError,
+		}
+		for statusCode, sentinel := range cases {
+			got := &APIError{StatusCode: statusCode}
+
+			if !errors.Is(got, sentinel) {
+				t.Fatalf("Error for status %d was expected to be %v", statusCode, sentinel)
+			}
+		}
+	})
+
+	t.Run("APIError is returned for failed request", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(headerRequestId, "123456789")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"code":"UNAUTHORIZED","message":"Unauthorized."}`)
+		}))
+		defer ts.Close()
+
+		_, err := client(ts.URL).CreateProcess(&CreateProcessRequest{})
+
+		var got *APIError
+		if !errors.As(err, &got) {
+			t.Fatalf("Error was expected to be APIError got %v", err)
+		}
+		if got.StatusCode != http.StatusUnauthorized || got.RequestId != "123456789" {
+			t.Fatalf("Error status or request id is incorrect got %d %s", got.StatusCode, got.RequestId)
+		}
+		if got.Code != "UNAUTHORIZED" || got.Message != "Unauthorized." {
+			t.Fatalf("Error code or message is incorrect got %s %s", got.Code, got.Message)
+		}
+		if got.Error() != "(123456789) request failed 401 UNAUTHORIZED: Unauthorized." {
+			t.Fatalf("Error message is incorrect got %s", got.Error())
+		}
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Fatalf("Error was expected to be ErrUnauthorized")
+		}
+	})
+
+	t.Run("APIError keeps raw body of non JSON response", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "Bad Gateway")
+		}))
+		defer ts.Close()
+
+		_, err := client(ts.URL).GetProcessStatus(&GetProcessStatusRequest{Id: "id"})
+
+		var got *APIError
+		if !errors.As(err, &got) {
+			t.Fatalf("Error was expected to be APIError got %v", err)
+		}
+		if got.Body != "Bad Gateway" {
+			t.Fatalf("Error body is incorrect got %s", got.Body)
+		}
+		if got.Code != "" {
+			t.Fatalf("Error code was expected to be empty got %s", got.Code)
+		}
+	})
 }
@@ -0,0 +1,229 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	jobJournalFile = "jobs"
+	jobOutputsDir  = "outputs"
+)
+
+// JobRecord is the last known state of a batch job.
+type JobRecord struct {
+	JobId     string `json:"jobId"`
+	InputHash string `json:"inputHash"`
+	ProcessId string `json:"processId,omitempty"`
+	Status    Status `json:"status,omitempty"`
+	// OutputPath is the location of the stored output of a completed job.
+	OutputPath string `json:"outputPath,omitempty"`
+}
+
+// JobJournal persists the state of batch jobs in a directory, so that a restarted batch resumes where the previous
+// one stopped. Jobs are identified by BatchJob.Id, which must be stable between runs. Jobs whose process was
+// cancelled, e.g. by interrupting the batch, are submitted again.
+type JobJournal struct {
+	mu      sync.Mutex
+	dir     string
+	file    *os.File
+	records map[string]JobRecord
+}
+
+// OpenJobJournal opens the journal in the directory, creating it when it does not exist yet.
+func OpenJobJournal(dir string) (*JobJournal, error) {
+	if err := os.MkdirAll(filepath.Join(dir, jobOutputsDir), 0700); err != nil {
+		return nil, NewClientErrorWithCause("failed to create job journal directory", err)
+	}
+
+	path := filepath.Join(dir, jobJournalFile)
+	records, err := readJobRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := compactJobRecords(path, records); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, NewClientErrorWithCause("failed to open job journal", err)
+	}
+
+	return &JobJournal{
+		dir:     dir,
+		file:    file,
+		records: records,
+	}, nil
+}
+
+// Record returns the last known state of the job.
+func (j *JobJournal) Record(jobId string) (JobRecord, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	record, ok := j.records[jobId]
+	return record, ok
+}
+
+// Close closes the journal.
+func (j *JobJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.file.Close()
+}
+
+// resumable returns the record of the job if it was already submitted with the same input and did not fail.
+func (j *JobJournal) resumable(job BatchJob) (JobRecord, bool) {
+	record, ok := j.Record(job.Id)
+	if !ok || record.InputHash != jobInputHash(job) || record.ProcessId == "" {
+		return JobRecord{}, false
+	}
+	if record.Status != "" && record.Status != StatusInProgress && record.Status != StatusCompleted {
+		return JobRecord{}, false
+	}
+	if record.Status == StatusCompleted && record.OutputPath == "" {
+		return JobRecord{}, false
+	}
+	return record, true
+}
+
+func (j *JobJournal) submitted(job BatchJob, processId string) error {
+	return j.write(JobRecord{
+		JobId:     job.Id,
+		InputHash: jobInputHash(job),
+		ProcessId: processId,
+		Status:    StatusInProgress,
+	})
+}
+
+func (j *JobJournal) finished(job BatchJob, processId string, status Status) error {
+	return j.write(JobRecord{
+		JobId:     job.Id,
+		InputHash: jobInputHash(job),
+		ProcessId: processId,
+		Status:    status,
+	})
+}
+
+func (j *JobJournal) completed(job BatchJob, processId string, output *Output) error {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return NewClientErrorWithCause("failed to serialize job output", err)
+	}
+
+	path := filepath.Join(j.dir, jobOutputsDir, hash([]byte(job.Id))+".json")
+	if err := writeAtomic(path, data, 0600); err != nil {
+		return err
+	}
+
+	return j.write(JobRecord{
+		JobId:      job.Id,
+		InputHash:  jobInputHash(job),
+		ProcessId:  processId,
+		Status:     StatusCompleted,
+		OutputPath: path,
+	})
+}
+
+func (j *JobJournal) output(record JobRecord) (*Output, error) {
+	data, err := os.ReadFile(record.OutputPath)
+	if err != nil {
+		return nil, NewClientErrorWithCause("failed to read job output", err)
+	}
+
+	var output Output
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, NewClientErrorWithCause("failed to parse job output", err)
+	}
+	return &output, nil
+}
+
+func (j *JobJournal) write(record JobRecord) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	data, err := json.Marshal(&record)
+	if err != nil {
+		return NewClientErrorWithCause("failed to serialize job record", err)
+	}
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return NewClientErrorWithCause("failed to write job record", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return NewClientErrorWithCause("failed to write job record", err)
+	}
+
+	j.records[record.JobId] = record
+	return nil
+}
+
+func readJobRecords(path string) (map[string]JobRecord, error) {
+	records := map[string]JobRecord{}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return records, nil
+		}
+		return nil, NewClientErrorWithCause("failed to open job journal", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var record JobRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// The last record may be incomplete when the previous run crashed while writing it.
+			continue
+		}
+		records[record.JobId] = record
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewClientErrorWithCause("failed to read job journal", err)
+	}
+	return records, nil
+}
+
+// compactJobRecords rewrites the journal with only the last record of every job, so that it does not grow with
+// every run.
+func compactJobRecords(path string, records map[string]JobRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var buf bytes.Buffer
+	for _, id := range ids {
+		record := records[id]
+		data, err := json.Marshal(&record)
+		if err != nil {
+			return NewClientErrorWithCause("failed to serialize job record", err)
+		}
+		buf.Write(append(data, '\n'))
+	}
+	return writeAtomic(path, buf.Bytes(), 0600)
+}
+
+func jobInputHash(job BatchJob) string {
+	data, _ := json.Marshal(&job.Process)
+	return hash(data)
+}
@@ -0,0 +1,247 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJobJournal(t *testing.T) {
+
+	countingServer := func(creates *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/process":
+				n := atomic.AddInt32(creates, 1)
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintf(w, `{"id": "new-%d"}`, n)
+			case "/process/status":
+				fmt.Fprintln(w, `{"status": "COMPLETED"}`)
+			case "/process/output":
+				fmt.Fprintln(w, `{"output": {"source": "source"}}`)
+			}
+		}))
+	}
+
+	jobs := []BatchJob{
+		{Id: "1", Process: Process{Mode: ModeDocument, Language: LanguageGo, Input: Input{Source: "1"}}},
+		{Id: "2", Process: Process{Mode: ModeDocument, Language: LanguageGo, Input: Input{Source: "2"}}},
+		{Id: "3", Process: Process{Mode: ModeDocument, Language: LanguageGo, Input: Input{Source: "3"}}},
+	}
+
+	run := func(t *testing.T, url string, journal *JobJournal, jobs []BatchJob) map[string]BatchResult {
+		processor := NewBatchProcessor(client(url), BatchConfig{
+			Poll:    pollConfig(),
+			Journal: journal,
+		})
+
+		got := map[string]BatchResult{}
+		for result := range processor.Process(context.Background(), jobs) {
+			if result.Err != nil {
+				t.Fatalf("Job %s failed with an error %v", result.JobId, result.Err)
+			}
+			got[result.JobId] = result
+		}
+		return got
+	}
+
+	t.Run("BatchProcessor resumes jobs recorded in the journal", func(t *testing.T) {
+		var creates int32
+		ts := countingServer(&creates)
+		defer ts.Close()
+
+		dir := t.TempDir()
+		journal, err := OpenJobJournal(dir)
+		if err != nil {
+			t.Fatalf("OpenJobJournal failed with an error %v", err)
+		}
+		journal.submitted(jobs[0], "submitted")
+		journal.completed(jobs[1], "completed", &Output{Source: "stored"})
+		journal.Close()
+
+		journal, err = OpenJobJournal(dir)
+		if err != nil {
+			t.Fatalf("OpenJobJournal failed with an error %v", err)
+		}
+		defer journal.Close()
+
+		got := run(t, ts.URL, journal, jobs)
+
+		if creates != 1 {
+			t.Fatalf("Only the unsubmitted job was expected to be created got %d", creates)
+		}
+		if got["1"].ProcessId != "submitted" || !got["1"].Resumed || got["1"].Output.Source != "source" {
+			t.Fatalf("Submitted job was expected to be resumed got %+v", got["1"])
+		}
+		if got["2"].ProcessId != "completed" || got["2"].Output.Source != "stored" {
+			t.Fatalf("Completed job was expected to be skipped got %+v", got["2"])
+		}
+		if got["3"].ProcessId != "new-1" || got["3"].Resumed {
+			t.Fatalf("Unsubmitted job was expected to be created got %+v", got["3"])
+		}
+
+		record, ok := journal.Record("3")
+		if !ok || record.Status != StatusCompleted || record.OutputPath == "" {
+			t.Fatalf("Job record was incorrect got %+v", record)
+		}
+	})
+
+	t.Run("BatchProcessor resubmits jobs with changed input", func(t *testing.T) {
+		var creates int32
+		ts := countingServer(&creates)
+		defer ts.Close()
+
+		journal, err := OpenJobJournal(t.TempDir())
+		if err != nil {
+			t.Fatalf("OpenJobJournal failed with an error %v", err)
+		}
+		defer journal.Close()
+
+		run(t, ts.URL, journal, jobs)
+		run(t, ts.URL, journal, jobs)
+		if creates != 3 {
+			t.Fatalf("Completed jobs were expected not to be created again got %d", creates)
+		}
+
+		changed := []BatchJob{{Id: "1", Process: Process{Mode: ModeDocument, Language: LanguageGo, Input: Input{Source: "changed"}}}}
+		got := run(t, ts.URL, journal, changed)
+		if creates != 4 || got["1"].Resumed {
+			t.Fatalf("Changed job was expected to be created again got %d", creates)
+		}
+	})
+
+	t.Run("BatchProcessor resubmits jobs with failed process", func(t *testing.T) {
+		var creates int32
+		ts := countingServer(&creates)
+		defer ts.Close()
+
+		journal, err := OpenJobJournal(t.TempDir())
+		if err != nil {
+			t.Fatalf("OpenJobJournal failed with an error %v", err)
+		}
+		defer journal.Close()
+		journal.finished(jobs[0], "failed", StatusFailed)
+
+		got := run(t, ts.URL, journal, jobs[:1])
+		if creates != 1 || got["1"].ProcessId != "new-1" {
+			t.Fatalf("Failed job was expected to be created again got %+v", got["1"])
+		}
+	})
+	t.Run("BatchProcessor resubmits jobs with cancelled process", func(t *testing.T) {
+		var creates int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/process":
+				n := atomic.AddInt32(&creates, 1)
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintf(w, `{"id": "new-%d"}`, n)
+			case "/process/status":
+				var request GetProcessStatusRequest
+				json.NewDecoder(r.Body).Decode(&request)
+				if request.Id == "cancelled" {
+					fmt.Fprintln(w, `{"status": "CANCELLED"}`)
+					return
+				}
+				fmt.Fprintln(w, `{"status": "COMPLETED"}`)
+			case "/process/output":
+				fmt.Fprintln(w, `{"output": {"source": "source"}}`)
+			}
+		}))
+		defer ts.Close()
+
+		journal, err := OpenJobJournal(t.TempDir())
+		if err != nil {
+			t.Fatalf("OpenJobJournal failed with an error %v", err)
+		}
+		defer journal.Close()
+		journal.submitted(jobs[0], "cancelled")
+
+		got := run(t, ts.URL, journal, jobs[:1])
+		if creates != 1 || got["1"].ProcessId != "new-1" || got["1"].Resumed {
+			t.Fatalf("Cancelled job was expected to be created again got %+v", got["1"])
+		}
+	})
+
+	t.Run("Interrupted jobs are recorded as cancelled", func(t *testing.T) {
+		ts := processServer(StatusInProgress)
+		defer ts.Close()
+
+		journal, err := OpenJobJournal(t.TempDir())
+		if err != nil {
+			t.Fatalf("OpenJobJournal failed with an error %v", err)
+		}
+		defer journal.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		processor := NewBatchProcessor(client(ts.URL), BatchConfig{
+			Poll:    pollConfig(),
+			Journal: journal,
+		})
+		for range processor.Process(ctx, jobs[:1]) {
+		}
+
+		if record, ok := journal.Record("1"); !ok || record.Status != StatusCancelled {
+			t.Fatalf("Job record was incorrect got %+v", record)
+		}
+	})
+
+	t.Run("Journal errors of failed jobs are reported", func(t *testing.T) {
+		ts := processServer(StatusFailed)
+		defer ts.Close()
+
+		journal, err := OpenJobJournal(t.TempDir())
+		if err != nil {
+			t.Fatalf("OpenJobJournal failed with an error %v", err)
+		}
+		journal.submitted(jobs[0], "id")
+		journal.Close()
+
+		processor := NewBatchProcessor(client(ts.URL), BatchConfig{
+			Poll:    pollConfig(),
+			Journal: journal,
+		})
+		result := <-processor.Process(context.Background(), jobs[:1])
+		if !errors.Is(result.Err, ErrProcessFailed) || !strings.Contains(result.Err.Error(), "failed to write job record") {
+			t.Fatalf("Error was expected to include the journal error got %v", result.Err)
+		}
+	})
+
+	t.Run("Journal is compacted when opened", func(t *testing.T) {
+		dir := t.TempDir()
+		journal, err := OpenJobJournal(dir)
+		if err != nil {
+			t.Fatalf("OpenJobJournal failed with an error %v", err)
+		}
+		journal.submitted(jobs[0], "first")
+		journal.submitted(jobs[0], "second")
+		journal.finished(jobs[0], "second", StatusFailed)
+		journal.Close()
+
+		journal, err = OpenJobJournal(dir)
+		if err != nil {
+			t.Fatalf("OpenJobJournal failed with an error %v", err)
+		}
+		defer journal.Close()
+
+		data, _ := os.ReadFile(filepath.Join(dir, jobJournalFile))
+		if lines := strings.Count(string(data), "\n"); lines != 1 {
+			t.Fatalf("Journal was expected to have one record got %d", lines)
+		}
+		if record, ok := journal.Record("1"); !ok || record.ProcessId != "second" || record.Status != StatusFailed {
+			t.Fatalf("Job record was incorrect got %+v", record)
+		}
+	})
+}
@@ -0,0 +1,246 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// LanguageUnsupported is the detected language of files that cannot be processed.
+const LanguageUnsupported Language = ""
+
+const (
+	binarySampleSize = 8000
+
+	confidenceExtension        = 0.9
+	confidenceShebang          = 0.9
+	confidenceAmbiguousMatch   = 0.8
+	confidenceAmbiguousDefault = 0.6
+	confidenceContent          = 0.7
+	confidenceContentMismatch  = 0.4
+)
+
+var languageExtensions = map[string]Language{
+	".c":    LanguageC,
+	".cpp":  LanguageCPP,
+	".cc":   LanguageCPP,
+	".cxx":  LanguageCPP,
+	".hpp":  LanguageCPP,
+	".hh":   LanguageCPP,
+	".js":   LanguageJavaScript,
+	".jsx":  LanguageJavaScript,
+	".mjs":  LanguageJavaScript,
+	".cjs":  LanguageJavaScript,
+	".php":  LanguagePHP,
+	".java": LanguageJava,
+	".cs":   LanguageCSharp,
+	".go":   LanguageGo,
+	".kt":   LanguageKotlin,
+	".kts":  LanguageKotlin,
+	".ts":   LanguageTypeScript,
+	".tsx":  LanguageTypeScript,
+	".mts":  LanguageTypeScript,
+	".rs":   LanguageRust,
+}
+
+var shebangInterpreters = map[string]Language{
+	"node":        LanguageJavaScript,
+	"nodejs":      LanguageJavaScript,
+	"bun":         LanguageJavaScript,
+	"deno":        LanguageTypeScript,
+	"ts-node":     LanguageTypeScript,
+	"tsx":         LanguageTypeScript,
+	"php":         LanguagePHP,
+	"kotlin":      LanguageKotlin,
+	"kscript":     LanguageKotlin,
+	"rust-script": LanguageRust,
+	"go":          LanguageGo,
+	"gorun":       LanguageGo,
+	"java":        LanguageJava,
+	"dotnet":      LanguageCSharp,
+}
+
+var cppMarkers = regexp.MustCompile(`(?m)\bclass\s+\w+|\bnamespace\s+\w+|\btemplate\s*<|\bstd::|^\s*(public|private|protected):|#include\s*<(iostream|string|vector|map|memory)>`)
+
+type contentPattern struct {
+	language Language
+	patterns []*regexp.Regexp
+}
+
+var contentPatterns = []contentPattern{
+	{LanguageGo, []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^package\s+\w+\s*$`),
+		regexp.MustCompile(`(?m)^func\s+(\(\w+\s+\*?\w+\)\s*)?\w+\(`),
+		regexp.MustCompile(`(?m)^import\s+\(`),
+	}},
+	{LanguageJava, []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^package\s+[\w.]+;`),
+		regexp.MustCompile(`(?m)^import\s+(static\s+)?java\.`),
+		regexp.MustCompile(`\bpublic\s+(final\s+)?class\s+\w+`),
+		regexp.MustCompile(`\bpublic\s+static\s+void\s+main\(`),
+	}},
+	{LanguageKotlin, []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^package\s+[\w.]+\s*$`),
+		regexp.MustCompile(`(?m)^\s*fun\s+\w+\(`),
+		regexp.MustCompile(`(?m)^\s*(val|var)\s+\w+\s*(:\s*\w+)?\s*=`),
+		regexp.MustCompile(`\bdata\s+class\s+\w+`),
+	}},
+	{LanguageRust, []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^\s*(pub\s+)?fn\s+\w+`),
+		regexp.MustCompile(`\blet\s+mut\s+\w+`),
+		regexp.MustCompile(`(?m)^use\s+(std|crate)::`),
+		regexp.MustCompile(`\bimpl\s+\w+`),
+	}},
+	{LanguageCSharp, []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^using\s+System(\.\w+)*;`),
+		regexp.MustCompile(`(?m)^namespace\s+[\w.]+`),
+		regexp.MustCompile(`\bpublic\s+(static\s+)?(partial\s+)?class\s+\w+`),
+		regexp.MustCompile(`\bConsole\.WriteLine\(`),
+	}},
+	{LanguagePHP, []*regexp.Regexp{
+		regexp.MustCompile(`^\s*<\?php`),
+		regexp.MustCompile(`\$\w+\s*=`),
+		regexp.MustCompile(`\bfunction\s+\w+\s*\(`),
+	}},
+	{LanguageCPP, []*regexp.Regexp{
+		regexp.MustCompile(`#include\s*<(iostream|string|vector|map|memory)>`),
+		regexp.MustCompile(`\bstd::`),
+		regexp.MustCompile(`\bnamespace\s+\w+`),
+		regexp.MustCompile(`\btemplate\s*<`),
+	}},
+	{LanguageC, []*regexp.Regexp{
+		regexp.MustCompile(`#include\s*<(stdio|stdlib|string|stdint|unistd)\.h>`),
+		regexp.MustCompile(`\bint\s+main\s*\(`),
+		regexp.MustCompile(`\b(printf|malloc|free)\s*\(`),
+	}},
+	{LanguageTypeScript, []*regexp.Regexp{
+		regexp.MustCompile(`\binterface\s+\w+\s*\{`),
+		regexp.MustCompile(`:\s*(string|number|boolean|void)\b`),
+		regexp.MustCompile(`(?m)^\s*export\s+(type|interface|enum)\s+\w+`),
+		regexp.MustCompile(`(?m)^\s*import\s+.+\s+from\s+['"]`),
+	}},
+	{LanguageJavaScript, []*regexp.Regexp{
+		regexp.MustCompile(`\bfunction\s+\w+\s*\(`),
+		regexp.MustCompile(`\b(const|let)\s+\w+\s*=\s*require\(`),
+		regexp.MustCompile(`\bmodule\.exports\b`),
+		regexp.MustCompile(`(?m)^\s*import\s+.+\s+from\s+['"]`),
+		regexp.MustCompile(`=>\s*\{`),
+	}},
+}
+
+type LanguageDetection struct {
+	// Language is one of the Language constants or LanguageUnsupported.
+	Language Language
+	// Confidence is between 0 and 1.
+	Confidence float64
+}
+
+func (d LanguageDetection) Supported() bool {
+	return d.Language != LanguageUnsupported
+}
+
+// DetectLanguage detects the language of the source file from its extension, shebang and content.
+func DetectLanguage(path string, source []byte) LanguageDetection {
+	if isBinary(source) {
+		return LanguageDetection{Language: LanguageUnsupported}
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".h" {
+		if cppMarkers.Match(source) {
+			return LanguageDetection{Language: LanguageCPP, Confidence: confidenceAmbiguousMatch}
+		}
+		return LanguageDetection{Language: LanguageC, Confidence: confidenceAmbiguousDefault}
+	}
+	if language, ok := languageExtensions[ext]; ok {
+		return LanguageDetection{Language: language, Confidence: confidenceExtension}
+	}
+
+	if language, ok := detectShebang(source); ok {
+		if language == LanguageUnsupported {
+			return LanguageDetection{Language: LanguageUnsupported}
+		}
+		return LanguageDetection{Language: language, Confidence: confidenceShebang}
+	}
+
+	maxConfidence := confidenceContent
+	if ext != "" {
+		maxConfidence = confidenceContentMismatch
+	}
+	return detectContent(source, maxConfidence)
+}
+
+// detectShebang returns the language of the shebang interpreter, or LanguageUnsupported for an unknown interpreter.
+func detectShebang(source []byte) (Language, bool) {
+	if !bytes.HasPrefix(source, []byte("#!")) {
+		return LanguageUnsupported, false
+	}
+
+	line := string(source[2:])
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	fields := strings.Fields(line)
+	for i, field := range fields {
+		name := filepath.Base(field)
+		if i == 0 && name == "env" {
+			continue
+		}
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		return shebangInterpreters[name], true
+	}
+	return LanguageUnsupported, true
+}
+
+func detectContent(source []byte, maxConfidence float64) LanguageDetection {
+	best := LanguageDetection{Language: LanguageUnsupported}
+	bestMatches := 0
+
+	for _, candidate := range contentPatterns {
+		matches := 0
+		for _, pattern := range candidate.patterns {
+			if pattern.Match(source) {
+				matches++
+			}
+		}
+		if matches > bestMatches {
+			bestMatches = matches
+			best = LanguageDetection{
+				Language:   candidate.language,
+				Confidence: maxConfidence * (1 - 0.5/float64(matches)),
+			}
+		}
+	}
+
+	if bestMatches < 2 {
+		return LanguageDetection{Language: LanguageUnsupported}
+	}
+	return best
+}
+
+// isBinary reports whether the source looks like binary content.
+func isBinary(source []byte) bool {
+	sample := source
+	if len(sample) > binarySampleSize {
+		sample = sample[:binarySampleSize]
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+
+	for len(sample) > 0 {
+		r, size := utf8.DecodeRune(sample)
+		if r == utf8.RuneError && size == 1 && len(sample) >= utf8.UTFMax {
+			return true
+		}
+		sample = sample[size:]
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+
+	t.Run("Language is detected from extension", func(t *testing.T) {
+		cases := map[string]Language{
+			"main.go":    LanguageGo,
+			"App.java":   LanguageJava,
+			"index.TS":   LanguageTypeScript,
+			"lib.rs":     LanguageRust,
+			"program.cs": LanguageCSharp,
+			"README.md":  LanguageUnsupported,
+		}
+		for path, expected := range cases {
+			got := DetectLanguage(path, []byte("source"))
+			if got.Language != expected {
+				t.Fatalf("Language of %s was incorrect got %s", path, got.Language)
+			}
+		}
+
+		if got := DetectLanguage("main.go", []byte("source")); got.Confidence != confidenceExtension {
+			t.Fatalf("Confidence was incorrect got %v", got.Confidence)
+		}
+	})
+
+	t.Run("Header language is detected from content", func(t *testing.T) {
+		c := DetectLanguage("util.h", []byte("int util();"))
+		if c.Language != LanguageC || c.Confidence != confidenceAmbiguousDefault {
+			t.Fatalf("Language was incorrect got %v", c)
+		}
+
+		cpp := DetectLanguage("util.h", []byte("namespace util {\nclass Util {};\n}"))
+		if cpp.Language != LanguageCPP || cpp.Confidence != confidenceAmbiguousMatch {
+			t.Fatalf("Language was incorrect got %v", cpp)
+		}
+	})
+
+	t.Run("Language is detected from shebang", func(t *testing.T) {
+		cases := map[string]Language{
+			"#!/usr/bin/env node\nconsole.log(1)":   LanguageJavaScript,
+			"#!/usr/bin/env -S deno run\nlet x = 1": LanguageTypeScript,
+			"#!/usr/bin/php\n<?php echo 1;":         LanguagePHP,
+			"#!/bin/bash\necho 1":                   LanguageUnsupported,
+		}
+		for source, expected := range cases {
+			got := DetectLanguage("script", []byte(source))
+			if got.Language != expected {
+				t.Fatalf("Language of %q was incorrect got %s", source, got.Language)
+			}
+		}
+	})
+
+	t.Run("Language is detected from content", func(t *testing.T) {
+		got := DetectLanguage("main", []byte("package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tfmt.Println()\n}\n"))
+		if got.Language != LanguageGo {
+			t.Fatalf("Language was incorrect got %s", got.Language)
+		}
+		if got.Confidence <= 0 || got.Confidence > confidenceContent {
+			t.Fatalf("Confidence was incorrect got %v", got.Confidence)
+		}
+
+		mismatch := DetectLanguage("notes.md", []byte("package main\n\nfunc main() {\n}\n"))
+		if mismatch.Confidence > confidenceContentMismatch {
+			t.Fatalf("Confidence was incorrect got %v", mismatch.Confidence)
+		}
+	})
+
+	t.Run("Binary file is not supported", func(t *testing.T) {
+		got := DetectLanguage("main.go", []byte("package\x00main"))
+		if got.Supported() {
+			t.Fatalf("Language was expected to be unsupported got %s", got.Language)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// processTrackingTTL is how long a process is tracked without its status being checked, e.g. after the wait for it
+// was abandoned.
+const processTrackingTTL = time.Hour
+
+type trackedProcess struct {
+	span      Span
+	mode      Mode
+	language  Language
+	status    Status
+	createdAt time.Time
+	checkedAt time.Time
+}
+
+// processTracker follows every created process until it reaches a final status, to report its lifecycle span and
+// metrics. Processes not checked within the TTL are evicted.
+type processTracker struct {
+	mu        sync.Mutex
+	processes map[string]*trackedProcess
+}
+
+// start tracks the process and returns the evicted processes.
+func (t *processTracker) start(id string, process *trackedProcess) []*trackedProcess {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.processes == nil {
+		t.processes = map[string]*trackedProcess{}
+	}
+
+	var evicted []*trackedProcess
+	for other, p := range t.processes {
+		if process.createdAt.Sub(p.checkedAt) > processTrackingTTL {
+			evicted = append(evicted, p)
+			delete(t.processes, other)
+		}
+	}
+
+	t.processes[id] = process
+	return evicted
+}
+
+// transition updates the status of the process and returns its previous status.
+func (t *processTracker) transition(id string, status Status) (Status, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	process, ok := t.processes[id]
+	if !ok {
+		return "", false
+	}
+	previous := process.status
+	process.status = status
+	process.checkedAt = time.Now()
+	return previous, true
+}
+
+func (t *processTracker) finish(id string) (*trackedProcess, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	process, ok := t.processes[id]
+	delete(t.processes, id)
+	return process, ok
+}
+
+func (c *HttpClient) trackProcess(ctx context.Context, request interface{}, response interface{}) {
+	if c.config.Tracer == nil && c.config.Metrics == nil && c.config.Logger == nil {
+		return
+	}
+
+	switch response := response.(type) {
+	case *CreateProcessResponse:
+		c.startProcess(ctx, request.(*CreateProcessRequest), response.Id)
+	case *GetProcessStatusResponse:
+		c.updateProcess(request.(*GetProcessStatusRequest).Id, response.Status)
+	case *CancelProcessResponse:
+		c.updateProcess(request.(*CancelProcessRequest).Id, StatusCancelled)
+	}
+}
+
+func (c *HttpClient) startProcess(ctx context.Context, request *CreateProcessRequest, id string) {
+	now := time.Now()
+	process := &trackedProcess{
+		span:      noopSpan{},
+		mode:      request.Process.Mode,
+		language:  request.Process.Language,
+		status:    StatusInProgress,
+		createdAt: now,
+		checkedAt: now,
+	}
+	if c.config.Tracer != nil {
+		_, process.span = c.config.Tracer.Start(ctx, SpanProcess,
+			append(requestAttributes(request), Attribute{Key: AttributeProcessId, Value: id})...)
+	}
+	for _, evicted := range c.processes.start(id, process) {
+		// The outcome of an evicted process is unknown, so only its span is ended.
+		evicted.span.SetAttributes(Attribute{Key: AttributeStatus, Value: evicted.status.String()})
+		evicted.span.End()
+	}
+
+	if c.config.Logger != nil {
+		c.config.Logger.LogAttrs(ctx, slog.LevelInfo, "process created",
+			append([]slog.Attr{slog.String("process_id", id)}, c.requestLogAttrs(request)...)...)
+	}
+}
+
+func (c *HttpClient) updateProcess(id string, status Status) {
+	if previous, ok := c.processes.transition(id, status); ok && previous != status {
+		c.logProcessStatus(id, previous, status)
+	}
+	if status != StatusInProgress {
+		c.finishProcess(id, status)
+	}
+}
+
+func (c *HttpClient) finishProcess(id string, status Status) {
+	process, ok := c.processes.finish(id)
+	if !ok {
+		return
+	}
+
+	process.span.SetAttributes(Attribute{Key: AttributeStatus, Value: status.String()})
+	if status != StatusCompleted {
+		process.span.RecordError(&ProcessError{Id: id, Status: status, cause: processStatusError(status)})
+	}
+	process.span.End()
+
+	if c.config.Metrics != nil {
+		labels := Labels{
+			LabelMode:     process.mode.String(),
+			LabelLanguage: process.language.String(),
+			LabelStatus:   status.String(),
+		}
+		c.config.Metrics.AddCounter(MetricProcesses, labels, 1)
+		c.config.Metrics.ObserveHistogram(MetricProcessDuration, labels, time.Since(process.createdAt).Seconds())
+	}
+}
+
+func processStatusError(status Status) error {
+	switch status {
+	case StatusFailed:
+		return ErrProcessFailed
+	case StatusTimedOut:
+		return ErrProcessTimedOut
+	case StatusCancelled:
+		return ErrProcessCancelled
+	}
+	return ErrProcessStatusUnexpected
+}
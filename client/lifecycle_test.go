@@ -0,0 +1,33 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProcessTracker(t *testing.T) {
+
+	t.Run("Processes not checked within the TTL are evicted", func(t *testing.T) {
+		tracker := &processTracker{}
+		now := time.Now()
+
+		tracker.start("abandoned", &trackedProcess{span: noopSpan{}, status: StatusInProgress, createdAt: now, checkedAt: now})
+		tracker.start("polled", &trackedProcess{span: noopSpan{}, status: StatusInProgress, createdAt: now, checkedAt: now})
+		tracker.processes["polled"].checkedAt = now.Add(processTrackingTTL)
+
+		later := now.Add(processTrackingTTL + time.Minute)
+		evicted := tracker.start("new", &trackedProcess{span: noopSpan{}, status: StatusInProgress, createdAt: later, checkedAt: later})
+
+		if len(evicted) != 1 {
+			t.Fatalf("Evicted processes were incorrect got %d", len(evicted))
+		}
+		if _, ok := tracker.processes["abandoned"]; ok {
+			t.Fatalf("Abandoned process was expected to be evicted")
+		}
+		if len(tracker.processes) != 2 {
+			t.Fatalf("Tracked processes were incorrect got %v", tracker.processes)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultLogSourceLength = 100
+
+	redacted = "[REDACTED]"
+)
+
+// LogSource controls how the process input source is logged.
+type LogSource int
+
+const (
+	// LogSourceOmit logs only the size of the source.
+	LogSourceOmit LogSource = iota
+	// LogSourceTruncate logs the beginning of the source, up to the configured length.
+	LogSourceTruncate
+	// LogSourceHash logs the SHA-256 hash of the source.
+	LogSourceHash
+	// LogSourceFull logs the whole source.
+	LogSourceFull
+)
+
+// redactedHeader logs the HTTP headers with the credentials removed.
+type redactedHeader http.Header
+
+func (h redactedHeader) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(h))
+	for name, values := range h {
+		value := ""
+		if len(values) > 0 {
+			value = values[0]
+		}
+		if http.CanonicalHeaderKey(name) == headerAuthorization {
+			value = redacted
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func (c *HttpClient) logRequest(ctx context.Context, op operation, attempt int, req *http.Request, request interface{}) {
+	logger := c.config.Logger
+	if logger == nil || !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", op.name),
+		slog.String("endpoint", op.path),
+		slog.Int("attempt", attempt),
+		slog.Any("headers", redactedHeader(req.Header)),
+	}
+	attrs = append(attrs, c.requestLogAttrs(request)...)
+	logger.LogAttrs(ctx, slog.LevelDebug, "request started", attrs...)
+}
+
+func (c *HttpClient) logResponse(ctx context.Context, op operation, attempt int, resp *http.Response, err error, duration time.Duration) {
+	logger := c.config.Logger
+	if logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", op.name),
+		slog.String("endpoint", op.path),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", duration),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status_code", resp.StatusCode))
+		if requestId := resp.Header.Get(headerRequestId); requestId != "" {
+			attrs = append(attrs, slog.String("request_id", requestId))
+		}
+	}
+	logger.LogAttrs(ctx, slog.LevelDebug, "request finished", attrs...)
+}
+
+func (c *HttpClient) logRetry(ctx context.Context, op operation, attempt int, delay time.Duration) {
+	logger := c.config.Logger
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(ctx, slog.LevelWarn, "retrying request",
+		slog.String("operation", op.name),
+		slog.String("endpoint", op.path),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay))
+}
+
+func (c *HttpClient) logError(ctx context.Context, op operation, request interface{}, err error) {
+	logger := c.config.Logger
+	if logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", op.name),
+		slog.String("endpoint", op.path),
+		slog.String("error", err.Error()),
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		attrs = append(attrs, slog.Int("status_code", apiErr.StatusCode))
+		if apiErr.RequestId != "" {
+			attrs = append(attrs, slog.String("request_id", apiErr.RequestId))
+		}
+	}
+	attrs = append(attrs, c.requestLogAttrs(request)...)
+
+	level := slog.LevelError
+	if ctx.Err() != nil {
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(ctx, level, "request failed", attrs...)
+}
+
+func (c *HttpClient) logProcessStatus(id string, previous Status, status Status) {
+	logger := c.config.Logger
+	if logger == nil {
+		return
+	}
+
+	level := slog.LevelInfo
+	if status != StatusInProgress && status != StatusCompleted {
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(context.Background(), level, "process status changed",
+		slog.String("process_id", id),
+		slog.String("previous_status", previous.String()),
+		slog.String("status", status.String()))
+}
+
+func (c *HttpClient) requestLogAttrs(request interface{}) []slog.Attr {
+	switch request := request.(type) {
+	case *CreateProcessRequest:
+		return append([]slog.Attr{
+			slog.String("mode", request.Process.Mode.String()),
+			slog.String("language", request.Process.Language.String()),
+		}, c.sourceLogAttrs(request.Process.Input.Source)...)
+	case *GetProcessStatusRequest:
+		return []slog.Attr{slog.String("process_id", request.Id)}
+	case *GetProcessOutputRequest:
+		return []slog.Attr{slog.String("process_id", request.Id)}
+	case *CancelProcessRequest:
+		return []slog.Attr{slog.String("process_id", request.Id)}
+	}
+	return nil
+}
+
+func (c *HttpClient) sourceLogAttrs(source string) []slog.Attr {
+	attrs := []slog.Attr{slog.Int("source_size", len(source))}
+
+	switch c.config.LogSource {
+	case LogSourceTruncate:
+		attrs = append(attrs, slog.String("source", truncate(source, c.config.logSourceLength())))
+	case LogSourceHash:
+		attrs = append(attrs, slog.String("source_sha256", hash([]byte(source))))
+	case LogSourceFull:
+		attrs = append(attrs, slog.String("source", source))
+	}
+	return attrs
+}
+
+func (c Config) logSourceLength() int {
+	if c.LogSourceLength != nil && *c.LogSourceLength > 0 {
+		return *c.LogSourceLength
+	}
+	return defaultLogSourceLength
+}
+
+// truncate shortens the text to the number of characters, marking the cut with an ellipsis.
+func truncate(text string, length int) string {
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+
+	i := 0
+	for n := 0; n < length; n++ {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return text[:i] + "…"
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type logRecorder struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (r *logRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.buf.Write(p)
+}
+
+func (r *logRecorder) records(t *testing.T) []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(r.buf.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Log record was not valid JSON %s", line)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func (r *logRecorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.buf.String()
+}
+
+func loggingClient(endpoint string, out *logRecorder, logSource LogSource) Client {
+	apiKey := "ABCDE-GHIJK-LMNOP-QRSTU-1"
+	maxAttempts := 2
+	baseDelay := time.Millisecond
+	logSourceLength := 4
+
+	return NewClient(Config{
+		ApiKey:   apiKey,
+		Endpoint: &endpoint,
+		Retry: &RetryConfig{
+			MaxAttempts: &maxAttempts,
+			BaseDelay:   &baseDelay,
+		},
+		Logger:          slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		LogSource:       logSource,
+		LogSourceLength: &logSourceLength,
+	})
+}
+
+func TestLogging(t *testing.T) {
+
+	request := &CreateProcessRequest{
+		Process: Process{
+			Mode:     ModeDocument,
+			Language: LanguageGo,
+			Input: Input{
+				Source: "package main",
+			},
+		},
+	}
+
+	t.Run("Client logs requests and process status transitions", func(t *testing.T) {
+		ts := processServer(StatusInProgress, StatusCompleted)
+		defer ts.Close()
+
+		out := &logRecorder{}
+		if _, err := RunProcess(context.Background(), loggingClient(ts.URL, out, LogSourceOmit), request, pollConfig()); err != nil {
+			t.Fatalf("RunProcess failed with an error %v", err)
+		}
+
+		messages := map[string]int{}
+		for _, record := range out.records(t) {
+			messages[record["msg"].(string)]++
+		}
+		if messages["request started"] != 4 || messages["request finished"] != 4 ||
+			messages["process created"] != 1 || messages["process status changed"] != 1 {
+			t.Fatalf("Log messages were incorrect got %v", messages)
+		}
+
+		got := out.String()
+		if strings.Contains(got, "ABCDE-GHIJK-LMNOP-QRSTU-1") || !strings.Contains(got, `"Authorization":"[REDACTED]"`) {
+			t.Fatalf("Authorization header was expected to be redacted got %s", got)
+		}
+		if strings.Contains(got, "package main") || !strings.Contains(got, `"source_size":12`) {
+			t.Fatalf("Source was expected to be omitted got %s", got)
+		}
+	})
+
+	t.Run("Client logs retries and failures", func(t *testing.T) {
+		var calls int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set(headerRequestId, "request-id")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, `{"code":"SERVICE_UNAVAILABLE"}`)
+		}))
+		defer ts.Close()
+
+		out := &logRecorder{}
+		if _, err := loggingClient(ts.URL, out, LogSourceOmit).GetProcessStatus(&GetProcessStatusRequest{Id: "id"}); err == nil {
+			t.Fatalf("Request was expected to fail")
+		}
+
+		records := out.records(t)
+		retry, failure := records[2], records[len(records)-1]
+		if retry["msg"] != "retrying request" || retry["level"] != "WARN" {
+			t.Fatalf("Retry log was incorrect got %v", retry)
+		}
+		if failure["msg"] != "request failed" || failure["level"] != "ERROR" ||
+			failure["request_id"] != "request-id" || failure["process_id"] != "id" {
+			t.Fatalf("Failure log was incorrect got %v", failure)
+		}
+	})
+
+	t.Run("Client truncates or hashes logged source", func(t *testing.T) {
+		ts := processServer(StatusCompleted)
+		defer ts.Close()
+
+		out := &logRecorder{}
+		loggingClient(ts.URL, out, LogSourceTruncate).CreateProcess(request)
+		if got := out.String(); !strings.Contains(got, `"source":"pack…"`) {
+			t.Fatalf("Source was expected to be truncated got %s", got)
+		}
+
+		out = &logRecorder{}
+		loggingClient(ts.URL, out, LogSourceHash).CreateProcess(request)
+		if got := out.String(); !strings.Contains(got, hash([]byte("package main"))) || strings.Contains(got, "package main") {
+			t.Fatalf("Source was expected to be hashed got %s", got)
+		}
+	})
+}
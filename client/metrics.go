@@ -0,0 +1,75 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// MetricRequests counts the request attempts by operation, endpoint and status class.
+	MetricRequests = "codemaker_requests_total"
+	// MetricRequestDuration observes the duration of the request attempts in seconds by operation and endpoint.
+	MetricRequestDuration = "codemaker_request_duration_seconds"
+	// MetricRetries counts the retried request attempts by operation and endpoint.
+	MetricRetries = "codemaker_retries_total"
+	// MetricProcesses counts the processes reaching a final status by mode, language and status.
+	MetricProcesses = "codemaker_processes_total"
+	// MetricProcessDuration observes the duration of the processes from creation to final status in seconds by mode,
+	// language and status.
+	MetricProcessDuration = "codemaker_process_duration_seconds"
+
+	LabelOperation   = "operation"
+	LabelEndpoint    = "endpoint"
+	LabelStatusClass = "status_class"
+	LabelMode        = "mode"
+	LabelLanguage    = "language"
+	LabelStatus      = "status"
+
+	// StatusClassError is the status class of requests that failed without an HTTP response.
+	StatusClassError = "error"
+)
+
+type Labels map[string]string
+
+// Metrics receives the measurements of the client. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// AddCounter adds the value to the counter with the labels.
+	AddCounter(name string, labels Labels, value float64)
+	// ObserveHistogram records the value in the histogram with the labels.
+	ObserveHistogram(name string, labels Labels, value float64)
+}
+
+func (c *HttpClient) recordRequest(op operation, resp *http.Response, err error, duration time.Duration) {
+	if c.config.Metrics == nil {
+		return
+	}
+
+	statusClass := StatusClassError
+	if err == nil {
+		statusClass = fmt.Sprintf("%dxx", resp.StatusCode/100)
+	}
+
+	c.config.Metrics.AddCounter(MetricRequests, Labels{
+		LabelOperation:   op.name,
+		LabelEndpoint:    op.path,
+		LabelStatusClass: statusClass,
+	}, 1)
+	c.config.Metrics.ObserveHistogram(MetricRequestDuration, Labels{
+		LabelOperation: op.name,
+		LabelEndpoint:  op.path,
+	}, duration.Seconds())
+}
+
+func (c *HttpClient) recordRetry(op operation) {
+	if c.config.Metrics == nil {
+		return
+	}
+
+	c.config.Metrics.AddCounter(MetricRetries, Labels{
+		LabelOperation: op.name,
+		LabelEndpoint:  op.path,
+	}, 1)
+}
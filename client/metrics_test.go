@@ -0,0 +1,121 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func metricsClient(endpoint string, metrics Metrics) Client {
+	apiKey := "ABCDE-GHIJK-LMNOP-QRSTU-1"
+	maxAttempts := 3
+	baseDelay := time.Millisecond
+
+	return NewClient(Config{
+		ApiKey:   apiKey,
+		Endpoint: &endpoint,
+		Retry: &RetryConfig{
+			MaxAttempts: &maxAttempts,
+			BaseDelay:   &baseDelay,
+		},
+		Metrics: metrics,
+	})
+}
+
+func TestMetrics(t *testing.T) {
+
+	t.Run("Client reports request and process metrics", func(t *testing.T) {
+		ts := processServer(StatusInProgress, StatusCompleted)
+		defer ts.Close()
+
+		metrics := NewPrometheusMetrics()
+		c := metricsClient(ts.URL, metrics)
+
+		_, err := RunProcess(context.Background(), c, &CreateProcessRequest{
+			Process: Process{
+				Mode:     ModeDocument,
+				Language: LanguageGo,
+			},
+		}, pollConfig())
+		if err != nil {
+			t.Fatalf("RunProcess failed with an error %v", err)
+		}
+
+		var out strings.Builder
+		metrics.WriteTo(&out)
+		got := out.String()
+
+		for _, expected := range []string{
+			"# TYPE codemaker_requests_total counter\n",
+			`codemaker_requests_total{endpoint="/process",operation="CreateProcess",status_class="2xx"} 1`,
+			`codemaker_requests_total{endpoint="/process/status",operation="GetProcessStatus",status_class="2xx"} 2`,
+			"# TYPE codemaker_request_duration_seconds histogram\n",
+			`codemaker_request_duration_seconds_count{endpoint="/process/output",operation="GetProcessOutput"} 1`,
+			`codemaker_processes_total{language="GO",mode="DOCUMENT",status="COMPLETED"} 1`,
+			`codemaker_process_duration_seconds_bucket{language="GO",mode="DOCUMENT",status="COMPLETED",le="+Inf"} 1`,
+		} {
+			if !strings.Contains(got, expected) {
+				t.Fatalf("Metrics were expected to contain %s got\n%s", expected, got)
+			}
+		}
+	})
+
+	t.Run("Client reports retries and status classes", func(t *testing.T) {
+		var calls int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintln(w, `{"status": "IN_PROGRESS"}`)
+		}))
+		defer ts.Close()
+
+		metrics := NewPrometheusMetrics()
+		c := metricsClient(ts.URL, metrics)
+
+		if _, err := c.GetProcessStatus(&GetProcessStatusRequest{Id: "id"}); err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		var out strings.Builder
+		metrics.WriteTo(&out)
+		got := out.String()
+
+		for _, expected := range []string{
+			`codemaker_requests_total{endpoint="/process/status",operation="GetProcessStatus",status_class="5xx"} 1`,
+			`codemaker_requests_total{endpoint="/process/status",operation="GetProcessStatus",status_class="2xx"} 1`,
+			`codemaker_retries_total{endpoint="/process/status",operation="GetProcessStatus"} 1`,
+		} {
+			if !strings.Contains(got, expected) {
+				t.Fatalf("Metrics were expected to contain %s got\n%s", expected, got)
+			}
+		}
+	})
+
+	t.Run("PrometheusMetrics writes histogram buckets", func(t *testing.T) {
+		metrics := NewPrometheusMetrics(1, 0.5)
+		metrics.ObserveHistogram("latency", Labels{"path": `a"b`}, 0.75)
+		metrics.ObserveHistogram("latency", Labels{"path": `a"b`}, 0.25)
+
+		var out strings.Builder
+		metrics.WriteTo(&out)
+
+		expected := "# TYPE latency histogram\n" +
+			`latency_bucket{path="a\"b",le="0.5"} 1` + "\n" +
+			`latency_bucket{path="a\"b",le="1"} 2` + "\n" +
+			`latency_bucket{path="a\"b",le="+Inf"} 2` + "\n" +
+			`latency_sum{path="a\"b"} 1` + "\n" +
+			`latency_count{path="a\"b"} 2` + "\n"
+		if out.String() != expected {
+			t.Fatalf("Metrics were incorrect got\n%s", out.String())
+		}
+	})
+}
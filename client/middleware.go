@@ -0,0 +1,36 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import "net/http"
+
+const (
+	OperationCreateProcess    = "CreateProcess"
+	OperationGetProcessStatus = "GetProcessStatus"
+	OperationGetProcessOutput = "GetProcessOutput"
+	OperationCancelProcess    = "CancelProcess"
+)
+
+// Call describes a single HTTP request attempt made by the client.
+type Call struct {
+	// Operation is the name of the client operation, e.g. OperationCreateProcess.
+	Operation string
+	// Request is the API request being sent, e.g. *CreateProcessRequest.
+	Request interface{}
+	// HttpRequest is the HTTP request that will be sent, it can be modified before calling the next handler.
+	HttpRequest *http.Request
+}
+
+// Handler sends the call and returns the HTTP response.
+type Handler func(call *Call) (*http.Response, error)
+
+// Middleware wraps the next handler. Middlewares run for every request attempt, including retries, after the
+// rate limiters have been waited on.
+type Middleware func(next Handler) Handler
+
+func chainMiddlewares(middlewares []Middleware, handler Handler) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	t.Run("Middlewares run in order for every request", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Trace-Id") != "trace" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintln(w, `{"id": "id"}`)
+		}))
+		defer ts.Close()
+
+		var order []string
+		var request *CreateProcessRequest
+		middleware := func(name string) Middleware {
+			return func(next Handler) Handler {
+				return func(call *Call) (*http.Response, error) {
+					order = append(order, name+":"+call.Operation)
+					if r, ok := call.Request.(*CreateProcessRequest); ok {
+						request = r
+					}
+					call.HttpRequest.Header.Set("X-Trace-Id", "trace")
+					resp, err := next(call)
+					order = append(order, name+":done")
+					return resp, err
+				}
+			}
+		}
+
+		endpoint := ts.URL
+		client := NewClient(Config{
+			Endpoint:    &endpoint,
+			Middlewares: []Middleware{middleware("first"), middleware("second")},
+		})
+
+		_, err := client.CreateProcess(&CreateProcessRequest{
+			Process: Process{
+				Mode: ModeDocument,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		expected := []string{"first:CreateProcess", "second:CreateProcess", "second:done", "first:done"}
+		if fmt.Sprint(order) != fmt.Sprint(expected) {
+			t.Fatalf("Middlewares order was incorrect got %v", order)
+		}
+		if request == nil || request.Process.Mode != ModeDocument {
+			t.Fatalf("Middleware was expected to receive the decoded request got %v", request)
+		}
+	})
+
+	t.Run("Middleware can short circuit the request", func(t *testing.T) {
+		endpoint := "http://127.0.0.1:0"
+		client := NewClient(Config{
+			Endpoint: &endpoint,
+			Middlewares: []Middleware{
+				func(next Handler) Handler {
+					return func(call *Call) (*http.Response, error) {
+						recorder := httptest.NewRecorder()
+						recorder.WriteHeader(http.StatusOK)
+						fmt.Fprint(recorder, `{"status": "COMPLETED"}`)
+						return recorder.Result(), nil
+					}
+				},
+			},
+		})
+
+		got, err := client.GetProcessStatus(&GetProcessStatusRequest{Id: "id"})
+		if err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		if got.Status != StatusCompleted {
+			t.Fatalf("Response status was incorrect got %s", got.Status)
+		}
+	})
+}
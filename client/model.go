@@ -3,38 +3,41 @@
 package client
 
 const (
-	ModeCompletion     = "COMPLETION"
-	ModeCode           = "CODE"
-	ModeInlineCode     = "INLINE_CODE"
-	ModeEditCode       = "EDIT_CODE"
-	ModeDocument       = "DOCUMENT"
-	ModeUnitTest       = "UNIT_TEST"
-	ModeMigrateSyntax  = "MIGRATE_SYNTAX"
-	ModeRefactorNaming = "REFACTOR_NAMING"
-	ModeFixSyntax      = "FIX_SYNTAX"
-
-	StatusInProgress = "IN_PROGRESS"
-	StatusCompleted  = "COMPLETED"
-	StatusFailed     = "FAILED"
-	StatusTimedOut   = "TIMED_OUT"
+	ModeCompletion     Mode = "COMPLETION"
+	ModeCode           Mode = "CODE"
+	ModeInlineCode     Mode = "INLINE_CODE"
+	ModeEditCode       Mode = "EDIT_CODE"
+	ModeDocument       Mode = "DOCUMENT"
+	ModeUnitTest       Mode = "UNIT_TEST"
+	ModeMigrateSyntax  Mode = "MIGRATE_SYNTAX"
+	ModeRefactorNaming Mode = "REFACTOR_NAMING"
+	ModeFixSyntax      Mode = "FIX_SYNTAX"
 )
 
 const (
-	LanguageC          = "C"
-	LanguageCPP        = "CPP"
-	LanguageJavaScript = "JAVASCRIPT"
-	LanguagePHP        = "PHP"
-	LanguageJava       = "JAVA"
-	LanguageCSharp     = "CSHARP"
-	LanguageGo         = "GO"
-	LanguageKotlin     = "KOTLIN"
-	LanguageTypeScript = "TYPESCRIPT"
-	LanguageRust       = "RUST"
+	StatusInProgress Status = "IN_PROGRESS"
+	StatusCompleted  Status = "COMPLETED"
+	StatusFailed     Status = "FAILED"
+	StatusTimedOut   Status = "TIMED_OUT"
+	StatusCancelled  Status = "CANCELLED"
 )
 
 const (
-	ModifyNone    = "NONE"
-	ModifyReplace = "REPLACE"
+	LanguageC          Language = "C"
+	LanguageCPP        Language = "CPP"
+	LanguageJavaScript Language = "JAVASCRIPT"
+	LanguagePHP        Language = "PHP"
+	LanguageJava       Language = "JAVA"
+	LanguageCSharp     Language = "CSHARP"
+	LanguageGo         Language = "GO"
+	LanguageKotlin     Language = "KOTLIN"
+	LanguageTypeScript Language = "TYPESCRIPT"
+	LanguageRust       Language = "RUST"
+)
+
+const (
+	ModifyNone    Modify = "NONE"
+	ModifyReplace Modify = "REPLACE"
 )
 
 type CreateProcessRequest struct {
@@ -50,7 +53,7 @@ type GetProcessStatusRequest struct {
 }
 
 type GetProcessStatusResponse struct {
-	Status string `json:"status"`
+	Status Status `json:"status"`
 }
 
 type GetProcessOutputRequest struct {
@@ -61,9 +64,16 @@ type GetProcessOutputResponse struct {
 	Output Output `json:"output"`
 }
 
+type CancelProcessRequest struct {
+	Id string `json:"id"`
+}
+
+type CancelProcessResponse struct {
+}
+
 type Process struct {
-	Mode     string   `json:"mode"`
-	Language string   `json:"language"`
+	Mode     Mode     `json:"mode"`
+	Language Language `json:"language"`
 	Input    Input    `json:"input"`
 	Options  *Options `json:"options"`
 }
@@ -75,7 +85,7 @@ type Input struct {
 type Options struct {
 	LanguageVersion *string `json:"languageVersion"`
 	Framework       *string `json:"framework"`
-	Modify          *string `json:"modify"`
+	Modify          *Modify `json:"modify"`
 	CodePath        *string `json:"codePath"`
 }
 
@@ -0,0 +1,211 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultHistogramBuckets are the upper bounds in seconds of the histogram buckets, covering both request and
+// process durations.
+var DefaultHistogramBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+var metricHelp = map[string]string{
+	MetricRequests:        "Total number of request attempts.",
+	MetricRequestDuration: "Duration of request attempts in seconds.",
+	MetricRetries:         "Total number of retried request attempts.",
+	MetricProcesses:       "Total number of processes by final status.",
+	MetricProcessDuration: "Duration of processes from creation to final status in seconds.",
+}
+
+type metricType string
+
+const (
+	metricTypeCounter   metricType = "counter"
+	metricTypeHistogram metricType = "histogram"
+)
+
+type series struct {
+	labels  Labels
+	value   float64
+	buckets []uint64
+	count   uint64
+}
+
+type family struct {
+	kind   metricType
+	series map[string]*series
+}
+
+// PrometheusMetrics is an in-memory Metrics exposing the measurements in the Prometheus text format. It is safe
+// for concurrent use.
+type PrometheusMetrics struct {
+	mu       sync.Mutex
+	buckets  []float64
+	families map[string]*family
+}
+
+// NewPrometheusMetrics creates the metrics with the histogram bucket upper bounds, defaults to
+// DefaultHistogramBuckets.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultHistogramBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusMetrics{
+		buckets:  buckets,
+		families: map[string]*family{},
+	}
+}
+
+func (m *PrometheusMetrics) AddCounter(name string, labels Labels, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s := m.series(name, metricTypeCounter, labels); s != nil {
+		s.value += value
+	}
+}
+
+func (m *PrometheusMetrics) ObserveHistogram(name string, labels Labels, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.series(name, metricTypeHistogram, labels)
+	if s == nil {
+		return
+	}
+	s.value += value
+	s.count++
+	for i, bound := range m.buckets {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	m.write(&buf)
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP serves the metrics for scraping.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// series returns the series of the metric with the labels, or nil when the metric was registered with another type.
+func (m *PrometheusMetrics) series(name string, kind metricType, labels Labels) *series {
+	f, ok := m.families[name]
+	if !ok {
+		f = &family{
+			kind:   kind,
+			series: map[string]*series{},
+		}
+		m.families[name] = f
+	}
+	if f.kind != kind {
+		return nil
+	}
+
+	key := formatLabels(labels, "")
+	s, ok := f.series[key]
+	if !ok {
+		copied := Labels{}
+		for k, v := range labels {
+			copied[k] = v
+		}
+		s = &series{labels: copied}
+		if kind == metricTypeHistogram {
+			s.buckets = make([]uint64, len(m.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (m *PrometheusMetrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.families))
+	for name := range m.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := m.families[name]
+		if help, ok := metricHelp[name]; ok {
+			fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+		}
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind == metricTypeCounter {
+				fmt.Fprintf(w, "%s%s %s\n", name, key, formatValue(s.value))
+				continue
+			}
+
+			for i, bound := range m.buckets {
+				fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(s.labels, formatValue(bound)), s.buckets[i])
+			}
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(s.labels, "+Inf"), s.count)
+			fmt.Fprintf(w, "%s_sum%s %s\n", name, key, formatValue(s.value))
+			fmt.Fprintf(w, "%s_count%s %d\n", name, key, s.count)
+		}
+	}
+}
+
+// formatLabels formats the labels sorted by name, adding the le label of a histogram bucket when le is set.
+func formatLabels(labels Labels, le string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", name, escapeLabelValue(labels[name])))
+	}
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf("le=\"%s\"", le))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter delays requests so that they do not exceed the allowed rate. Implementations must be safe for
+// concurrent use.
+type RateLimiter interface {
+	// Wait blocks until the request is allowed or the context is done.
+	Wait(ctx context.Context) error
+}
+
+// reservingRateLimiter is implemented by the limiters that can return a taken token, so that the tokens of several
+// limiters are taken together and returned when the wait is cancelled.
+type reservingRateLimiter interface {
+	RateLimiter
+	reserve() time.Duration
+	release()
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a token bucket RateLimiter allowing requestsPerSecond on average with bursts of up to
+// burst requests.
+func NewRateLimiter(requestsPerSecond float64, burst int) RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if b.rate <= 0 {
+		return nil
+	}
+
+	delay := b.reserve()
+	if err := sleep(ctx, delay); err != nil {
+		b.release()
+		return err
+	}
+	return nil
+}
+
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate <= 0 {
+		return
+	}
+
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// waitAll waits until every limiter allows the request. The tokens of the limiters implementing
+// reservingRateLimiter are reserved together after the other limiters allowed the request, and returned when the
+// wait is cancelled.
+func waitAll(ctx context.Context, limiters ...RateLimiter) error {
+	var reserving []reservingRateLimiter
+	for _, limiter := range limiters {
+		if r, ok := limiter.(reservingRateLimiter); ok {
+			reserving = append(reserving, r)
+			continue
+		}
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var delay time.Duration
+	for _, r := range reserving {
+		delay = max(delay, r.reserve())
+	}
+	if err := sleep(ctx, delay); err != nil {
+		for _, r := range reserving {
+			r.release()
+		}
+		return err
+	}
+	return nil
+}
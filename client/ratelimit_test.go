@@ -0,0 +1,127 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+
+	t.Run("RateLimiter allows burst without waiting", func(t *testing.T) {
+		limiter := NewRateLimiter(1, 3)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Fatalf("Wait failed with an error %v", err)
+			}
+		}
+
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Fatalf("Burst was expected not to wait got %v", elapsed)
+		}
+	})
+
+	t.Run("RateLimiter delays requests above the rate", func(t *testing.T) {
+		limiter := NewRateLimiter(50, 1)
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				limiter.Wait(context.Background())
+			}()
+		}
+		wg.Wait()
+
+		if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+			t.Fatalf("Requests were expected to be delayed got %v", elapsed)
+		}
+	})
+
+	t.Run("RateLimiter respects context cancellation", func(t *testing.T) {
+		limiter := NewRateLimiter(0.1, 1)
+		limiter.Wait(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := limiter.Wait(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Error was expected to be context.DeadlineExceeded got %v", err)
+		}
+	})
+
+	t.Run("Client waits on endpoint rate limiter", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"status": "IN_PROGRESS"}`)
+		}))
+		defer ts.Close()
+
+		endpoint := ts.URL
+		client := NewClient(Config{
+			Endpoint: &endpoint,
+			EndpointRateLimiters: map[string]RateLimiter{
+				"/process/status": NewRateLimiter(0.1, 1),
+			},
+		})
+
+		if _, err := client.GetProcessStatus(&GetProcessStatusRequest{Id: "id"}); err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetProcessStatusWithContext(ctx, &GetProcessStatusRequest{Id: "id"})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Error was expected to be context.DeadlineExceeded got %v", err)
+		}
+	})
+	t.Run("Client returns global token when endpoint wait is cancelled", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"status": "IN_PROGRESS"}`)
+		}))
+		defer ts.Close()
+
+		global := NewRateLimiter(0.1, 1)
+		endpointLimiter := NewRateLimiter(0.1, 1)
+		endpointLimiter.Wait(context.Background())
+
+		endpoint := ts.URL
+		client := NewClient(Config{
+			Endpoint:    &endpoint,
+			RateLimiter: global,
+			EndpointRateLimiters: map[string]RateLimiter{
+				"/process/status": endpointLimiter,
+			},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetProcessStatusWithContext(ctx, &GetProcessStatusRequest{Id: "id"})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Error was expected to be context.DeadlineExceeded got %v", err)
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := global.Wait(ctx); err != nil {
+			t.Fatalf("Global token was expected to be returned got %v", err)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBaseDelay   = 500 * time.Millisecond
+	defaultRetryMaxDelay    = 30 * time.Second
+	defaultRetryJitter      = 0.2
+
+	headerRetryAfter = "Retry-After"
+)
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryConfig controls automatic retries of failed requests. Each unset field falls back to its default.
+//
+// Requests that are not idempotent, such as CreateProcess, are only retried when the server could not have
+// started processing them: when the connection could not be established or the server responded with
+// 429 Too Many Requests.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts *int
+	// BaseDelay is the delay before the first retry, doubled on every subsequent retry.
+	BaseDelay *time.Duration
+	// MaxDelay caps the delay between attempts, including the delay requested by the server with Retry-After.
+	MaxDelay *time.Duration
+	// Jitter is the fraction, between 0 and 1, by which the backoff delay is randomly reduced.
+	Jitter *float64
+	// RetryableStatusCodes are the HTTP status codes that are retried.
+	RetryableStatusCodes []int
+	// IsRetryableError decides whether a transport error of an idempotent request is retried.
+	IsRetryableError func(err error) bool
+}
+
+func (c *RetryConfig) maxAttempts() int {
+	if c == nil {
+		return 1
+	}
+	if c.MaxAttempts != nil && *c.MaxAttempts > 0 {
+		return *c.MaxAttempts
+	}
+	return defaultRetryMaxAttempts
+}
+
+func (c *RetryConfig) baseDelay() time.Duration {
+	if c.BaseDelay != nil && *c.BaseDelay > 0 {
+		return *c.BaseDelay
+	}
+	return defaultRetryBaseDelay
+}
+
+func (c *RetryConfig) maxDelay() time.Duration {
+	if c.MaxDelay != nil && *c.MaxDelay > 0 {
+		return *c.MaxDelay
+	}
+	return defaultRetryMaxDelay
+}
+
+func (c *RetryConfig) jitter() float64 {
+	if c.Jitter != nil && *c.Jitter >= 0 && *c.Jitter <= 1 {
+		return *c.Jitter
+	}
+	return defaultRetryJitter
+}
+
+func (c *RetryConfig) isRetryableStatus(statusCode int) bool {
+	codes := c.RetryableStatusCodes
+	if codes == nil {
+		codes = defaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *RetryConfig) shouldRetry(idempotent bool, resp *http.Response, err error) bool {
+	if err != nil {
+		if isDialError(err) {
+			return true
+		}
+		if !idempotent {
+			return false
+		}
+		if c.IsRetryableError != nil {
+			return c.IsRetryableError(err)
+		}
+		return isTransientError(err)
+	}
+
+	if !idempotent {
+		return resp.StatusCode == http.StatusTooManyRequests && c.isRetryableStatus(resp.StatusCode)
+	}
+	return c.isRetryableStatus(resp.StatusCode)
+}
+
+func (c *RetryConfig) delay(attempt int, resp *http.Response) time.Duration {
+	backoff := float64(c.baseDelay()) * math.Pow(2, float64(attempt-1))
+	if backoff > float64(c.maxDelay()) {
+		backoff = float64(c.maxDelay())
+	}
+	delay := time.Duration(backoff * (1 - c.jitter()*rand.Float64()))
+
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get(headerRetryAfter)); ok && retryAfter > delay {
+			delay = min(retryAfter, c.maxDelay())
+		}
+	}
+	return delay
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func isTransientError(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func retryClient(endpoint string) Client {
+	apiKey := "ABCDE-GHIJK-LMNOP-QRSTU-1"
+	maxAttempts := 3
+	baseDelay := time.Millisecond
+
+	return NewClient(Config{
+		ApiKey:   apiKey,
+		Endpoint: &endpoint,
+		Retry: &RetryConfig{
+			MaxAttempts: &maxAttempts,
+			BaseDelay:   &baseDelay,
+		},
+	})
+}
+
+func TestRetry(t *testing.T) {
+
+	t.Run("GetProcessStatus is retried on service unavailable", func(t *testing.T) {
+		var attempts int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"status": "COMPLETED"}`)
+		}))
+		defer ts.Close()
+
+		client := retryClient(ts.URL)
+
+		got, err := client.GetProcessStatus(&GetProcessStatusRequest{
+			Id: "id",
+		})
+		if err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		if got.Status != StatusCompleted {
+			t.Fatalf("Response status was incorrect got %s", got.Status)
+		}
+		if attempts != 3 {
+			t.Fatalf("Request was expected to be attempted 3 times got %d", attempts)
+		}
+	})
+
+	t.Run("GetProcessOutput fails after max attempts", func(t *testing.T) {
+		var attempts int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.Header().Set(headerRequestId, "123456789")
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer ts.Close()
+
+		client := retryClient(ts.URL)
+
+		_, err := client.GetProcessOutput(&GetProcessOutputRequest{
+			Id: "id",
+		})
+
+		if err == nil {
+			t.Fatalf("Error was expected")
+		}
+		if err.Error() != "(123456789) request failed 502 " {
+			t.Fatalf("Incorrect error message %v", err)
+		}
+		if attempts != 3 {
+			t.Fatalf("Request was expected to be attempted 3 times got %d", attempts)
+		}
+	})
+
+	t.Run("CreateProcess is retried on too many requests", func(t *testing.T) {
+		var attempts int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.Header().Set(headerRetryAfter, "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintln(w, `{"id": "id"}`)
+		}))
+		defer ts.Close()
+
+		client := retryClient(ts.URL)
+
+		got, err := client.CreateProcess(&CreateProcessRequest{})
+		if err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		if got.Id != "id" {
+			t.Fatalf("Response id was incorrect got %s", got.Id)
+		}
+		if attempts != 2 {
+			t.Fatalf("Request was expected to be attempted 2 times got %d", attempts)
+		}
+	})
+
+	t.Run("CreateProcess is not retried on service unavailable", func(t *testing.T) {
+		var attempts int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		client := retryClient(ts.URL)
+
+		_, err := client.CreateProcess(&CreateProcessRequest{})
+
+		if err == nil {
+			t.Fatalf("Error was expected")
+		}
+		if attempts != 1 {
+			t.Fatalf("Request was expected to be attempted once got %d", attempts)
+		}
+	})
+
+	t.Run("Request is not retried without retry config", func(t *testing.T) {
+		var attempts int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		_, err := client.GetProcessStatus(&GetProcessStatusRequest{
+			Id: "id",
+		})
+
+		if err == nil {
+			t.Fatalf("Error was expected")
+		}
+		if attempts != 1 {
+			t.Fatalf("Request was expected to be attempted once got %d", attempts)
+		}
+	})
+
+	t.Run("Retry-After header is parsed", func(t *testing.T) {
+		got, ok := parseRetryAfter("2")
+		if !ok || got != 2*time.Second {
+			t.Fatalf("Retry-After was incorrect got %v", got)
+		}
+
+		if _, ok := parseRetryAfter("invalid"); ok {
+			t.Fatalf("Invalid Retry-After was expected to be ignored")
+		}
+	})
+	t.Run("Retry-After header is capped by max delay", func(t *testing.T) {
+		maxDelay := time.Second
+		config := &RetryConfig{MaxDelay: &maxDelay}
+		resp := &http.Response{Header: http.Header{headerRetryAfter: []string{"3600"}}}
+
+		if got := config.delay(1, resp); got != maxDelay {
+			t.Fatalf("Delay was incorrect got %v", got)
+		}
+	})
+}
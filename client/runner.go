@@ -0,0 +1,169 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPollInterval    = 1 * time.Second
+	defaultMaxPollInterval = 10 * time.Second
+	defaultPollMultiplier  = 1.5
+
+	cancelProcessTimeout = 5 * time.Second
+)
+
+var (
+	ErrProcessFailed           = errors.New("process failed")
+	ErrProcessTimedOut         = errors.New("process timed out")
+	ErrProcessCancelled        = errors.New("process was cancelled")
+	ErrProcessWaitExceeded     = errors.New("process did not finish within the maximum wait time")
+	ErrProcessStatusUnexpected = errors.New("process finished with an unexpected status")
+)
+
+// PollConfig controls how the process status is polled. Each unset field falls back to its default.
+type PollConfig struct {
+	// Interval is the delay before the first status check.
+	Interval *time.Duration
+	// MaxInterval caps the delay between status checks.
+	MaxInterval *time.Duration
+	// Multiplier is applied to the delay after every status check.
+	Multiplier *float64
+	// MaxWait limits the total time spent waiting for the process to finish. The process is not cancelled when the
+	// wait is exceeded, so that it can still be waited for again.
+	MaxWait *time.Duration
+}
+
+// ProcessError is returned when a process did not complete successfully.
+type ProcessError struct {
+	Id     string
+	Status Status
+	cause  error
+}
+
+func (e *ProcessError) Error() string {
+	return fmt.Sprintf("(%s) %s: %s", e.Id, e.cause, e.Status)
+}
+
+func (e *ProcessError) Unwrap() error {
+	return e.cause
+}
+
+// RunProcess creates the process, waits for it to finish and returns its output.
+func RunProcess(ctx context.Context, client Client, request *CreateProcessRequest, config PollConfig) (*Output, error) {
+	created, err := client.CreateProcessWithContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := WaitForProcess(ctx, client, created.Id, config); err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetProcessOutputWithContext(ctx, &GetProcessOutputRequest{
+		Id: created.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &output.Output, nil
+}
+
+// WaitForProcess polls the process status until the process leaves StatusInProgress and returns the final status.
+// A process that did not complete successfully results in a *ProcessError. When the context is cancelled while
+// the process is still in progress, the process is cancelled as well. Exceeding PollConfig.MaxWait results in
+// ErrProcessWaitExceeded without cancelling the process.
+func WaitForProcess(ctx context.Context, client Client, id string, config PollConfig) (Status, error) {
+	interval := config.interval()
+	maxInterval := config.maxInterval()
+	multiplier := config.multiplier()
+
+	parent := ctx
+	if config.MaxWait != nil && *config.MaxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, *config.MaxWait)
+		defer cancel()
+	}
+
+	status := StatusInProgress
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if parent.Err() == nil {
+				return status, &ProcessError{Id: id, Status: status, cause: ErrProcessWaitExceeded}
+			}
+			cancelProcess(parent, client, id)
+			return status, NewClientErrorWithCause("waiting for process was interrupted by the context", ctx.Err())
+		case <-timer.C:
+		}
+
+		resp, err := client.GetProcessStatusWithContext(ctx, &GetProcessStatusRequest{
+			Id: id,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return status, err
+		}
+		status = resp.Status
+
+		switch status {
+		case StatusInProgress:
+		case StatusCompleted:
+			return status, nil
+		case StatusFailed:
+			return status, &ProcessError{Id: id, Status: status, cause: ErrProcessFailed}
+		case StatusTimedOut:
+			return status, &ProcessError{Id: id, Status: status, cause: ErrProcessTimedOut}
+		case StatusCancelled:
+			return status, &ProcessError{Id: id, Status: status, cause: ErrProcessCancelled}
+		default:
+			return status, &ProcessError{Id: id, Status: status, cause: ErrProcessStatusUnexpected}
+		}
+
+		interval = time.Duration(float64(interval) * multiplier)
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+		timer.Reset(interval)
+	}
+}
+
+// cancelProcess makes a best effort attempt to cancel the process, detached from the already cancelled context.
+func cancelProcess(ctx context.Context, client Client, id string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelProcessTimeout)
+	defer cancel()
+
+	client.CancelProcessWithContext(ctx, &CancelProcessRequest{
+		Id: id,
+	})
+}
+
+func (c PollConfig) interval() time.Duration {
+	if c.Interval != nil && *c.Interval > 0 {
+		return *c.Interval
+	}
+	return defaultPollInterval
+}
+
+func (c PollConfig) maxInterval() time.Duration {
+	if c.MaxInterval != nil && *c.MaxInterval > 0 {
+		return *c.MaxInterval
+	}
+	return defaultMaxPollInterval
+}
+
+func (c PollConfig) multiplier() float64 {
+	if c.Multiplier != nil && *c.Multiplier >= 1 {
+		return *c.Multiplier
+	}
+	return defaultPollMultiplier
+}
@@ -0,0 +1,219 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func processServer(statuses ...Status) *httptest.Server {
+	return processServerWithCancel(nil, statuses...)
+}
+
+func processServerWithCancel(cancelled chan<- string, statuses ...Status) *httptest.Server {
+	var polls int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/process":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintln(w, `{"id": "id"}`)
+		case "/process/status":
+			i := int(atomic.AddInt32(&polls, 1)) - 1
+			if i >= len(statuses) {
+				i = len(statuses) - 1
+			}
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"status": "%s"}`, statuses[i])
+		case "/process/output":
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"output": {"source": "source"}}`)
+		case "/process/cancel":
+			var request CancelProcessRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if cancelled != nil {
+				cancelled <- request.Id
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func pollConfig() PollConfig {
+	interval := time.Millisecond
+	return PollConfig{
+		Interval: &interval,
+	}
+}
+
+func TestRunProcess(t *testing.T) {
+
+	t.Run("RunProcess returns the output of completed process", func(t *testing.T) {
+		ts := processServer(StatusInProgress, StatusInProgress, StatusCompleted)
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		got, err := RunProcess(context.Background(), client, &CreateProcessRequest{
+			Process: Process{
+				Mode:     ModeDocument,
+				Language: LanguageJava,
+			},
+		}, pollConfig())
+		if err != nil {
+			t.Fatalf("RunProcess failed with an error %v", err)
+		}
+
+		if got == nil {
+			t.Fatalf("Output was expect not to be nil")
+		}
+		if got.Source != "source" {
+			t.Fatalf("Output source was incorrect got %s", got.Source)
+		}
+	})
+
+	t.Run("RunProcess results in failed process error", func(t *testing.T) {
+		ts := processServer(StatusInProgress, StatusFailed)
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		_, err := RunProcess(context.Background(), client, &CreateProcessRequest{}, pollConfig())
+
+		if !errors.Is(err, ErrProcessFailed) {
+			t.Fatalf("Error was expected to be ErrProcessFailed got %v", err)
+		}
+
+		var processErr *ProcessError
+		if !errors.As(err, &processErr) {
+			t.Fatalf("Error was expected to be ProcessError got %v", err)
+		}
+		if processErr.Id != "id" || processErr.Status != StatusFailed {
+			t.Fatalf("Process error was incorrect got %v", processErr)
+		}
+	})
+
+	t.Run("WaitForProcess results in timed out process error", func(t *testing.T) {
+		ts := processServer(StatusTimedOut)
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		status, err := WaitForProcess(context.Background(), client, "id", pollConfig())
+
+		if !errors.Is(err, ErrProcessTimedOut) {
+			t.Fatalf("Error was expected to be ErrProcessTimedOut got %v", err)
+		}
+		if status != StatusTimedOut {
+			t.Fatalf("Status was incorrect got %s", status)
+		}
+	})
+
+	t.Run("WaitForProcess results in wait exceeded error", func(t *testing.T) {
+		ts := processServer(StatusInProgress)
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		config := pollConfig()
+		maxWait := 20 * time.Millisecond
+		config.MaxWait = &maxWait
+
+		status, err := WaitForProcess(context.Background(), client, "id", config)
+
+		if !errors.Is(err, ErrProcessWaitExceeded) {
+			t.Fatalf("Error was expected to be ErrProcessWaitExceeded got %v", err)
+		}
+		if status != StatusInProgress {
+			t.Fatalf("Status was incorrect got %s", status)
+		}
+	})
+
+	t.Run("WaitForProcess results in cancellation error", func(t *testing.T) {
+		ts := processServer(StatusInProgress)
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := WaitForProcess(ctx, client, "id", pollConfig())
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Error was expected to be context.DeadlineExceeded got %v", err)
+		}
+		if errors.Is(err, ErrProcessWaitExceeded) {
+			t.Fatalf("Error was not expected to be ErrProcessWaitExceeded")
+		}
+	})
+
+	t.Run("WaitForProcess results in cancelled process error", func(t *testing.T) {
+		ts := processServer(StatusCancelled)
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		_, err := WaitForProcess(context.Background(), client, "id", pollConfig())
+
+		if !errors.Is(err, ErrProcessCancelled) {
+			t.Fatalf("Error was expected to be ErrProcessCancelled got %v", err)
+		}
+	})
+
+	t.Run("WaitForProcess cancels the process when context is cancelled", func(t *testing.T) {
+		cancelled := make(chan string, 1)
+		ts := processServerWithCancel(cancelled, StatusInProgress)
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		WaitForProcess(ctx, client, "process-1", pollConfig())
+
+		select {
+		case id := <-cancelled:
+			if id != "process-1" {
+				t.Fatalf("Cancelled process id was incorrect got %s", id)
+			}
+		default:
+			t.Fatalf("Process was expected to be cancelled")
+		}
+	})
+	t.Run("WaitForProcess does not cancel the process when max wait is exceeded", func(t *testing.T) {
+		cancelled := make(chan string, 1)
+		ts := processServerWithCancel(cancelled, StatusInProgress)
+		defer ts.Close()
+
+		client := client(ts.URL)
+
+		config := pollConfig()
+		maxWait := 20 * time.Millisecond
+		config.MaxWait = &maxWait
+
+		_, err := WaitForProcess(context.Background(), client, "process-1", config)
+
+		if !errors.Is(err, ErrProcessWaitExceeded) {
+			t.Fatalf("Error was expected to be ErrProcessWaitExceeded got %v", err)
+		}
+		select {
+		case id := <-cancelled:
+			t.Fatalf("Process was expected not to be cancelled got %s", id)
+		default:
+		}
+	})
+}
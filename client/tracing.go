@@ -0,0 +1,96 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	SpanProcess = "codemaker.process"
+
+	AttributeOperation      = "codemaker.operation"
+	AttributeMode           = "codemaker.process.mode"
+	AttributeLanguage       = "codemaker.process.language"
+	AttributeSourceSize     = "codemaker.process.source_size"
+	AttributeProcessId      = "codemaker.process.id"
+	AttributeStatus         = "codemaker.process.status"
+	AttributeRequestId      = "codemaker.request_id"
+	AttributeHttpStatusCode = "http.response.status_code"
+)
+
+// Attribute is a span attribute, the value is a string, int, int64, float64 or bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer creates the spans of the client calls. It is intended to be backed by a tracing library, such as
+// OpenTelemetry, through an adapter.
+type Tracer interface {
+	// Start starts a span as a child of the span in the context and returns the context holding the new span.
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+	// Inject adds the headers propagating the trace context of the context to an outgoing request.
+	Inject(ctx context.Context, header http.Header)
+}
+
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	// RecordError marks the span as failed with the error.
+	RecordError(err error)
+	End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+
+func (noopSpan) RecordError(error) {}
+
+func (noopSpan) End() {}
+
+func (c *HttpClient) startSpan(ctx context.Context, op operation, request interface{}) (context.Context, Span) {
+	if c.config.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return c.config.Tracer.Start(ctx, "codemaker."+op.name, append(
+		[]Attribute{{Key: AttributeOperation, Value: op.name}}, requestAttributes(request)...)...)
+}
+
+func (c *HttpClient) endSpan(span Span, response interface{}, err error) {
+	if err != nil {
+		span.RecordError(err)
+	} else {
+		span.SetAttributes(responseAttributes(response)...)
+	}
+	span.End()
+}
+
+func requestAttributes(request interface{}) []Attribute {
+	switch request := request.(type) {
+	case *CreateProcessRequest:
+		return []Attribute{
+			{Key: AttributeMode, Value: request.Process.Mode.String()},
+			{Key: AttributeLanguage, Value: request.Process.Language.String()},
+			{Key: AttributeSourceSize, Value: len(request.Process.Input.Source)},
+		}
+	case *GetProcessStatusRequest:
+		return []Attribute{{Key: AttributeProcessId, Value: request.Id}}
+	case *GetProcessOutputRequest:
+		return []Attribute{{Key: AttributeProcessId, Value: request.Id}}
+	case *CancelProcessRequest:
+		return []Attribute{{Key: AttributeProcessId, Value: request.Id}}
+	}
+	return nil
+}
+
+func responseAttributes(response interface{}) []Attribute {
+	switch response := response.(type) {
+	case *CreateProcessResponse:
+		return []Attribute{{Key: AttributeProcessId, Value: response.Id}}
+	case *GetProcessStatusResponse:
+		return []Attribute{{Key: AttributeStatus, Value: response.Status.String()}}
+	}
+	return nil
+}
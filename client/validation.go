@@ -0,0 +1,194 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxSourceSize is the largest source in bytes accepted by the validation unless the capabilities set another one.
+const MaxSourceSize = 1024 * 1024
+
+var ErrInvalidRequest = errors.New("invalid request")
+
+var (
+	// codePathModes are the modes that can be limited to a single code element.
+	codePathModes = map[Mode]bool{
+		ModeCode:           true,
+		ModeInlineCode:     true,
+		ModeEditCode:       true,
+		ModeDocument:       true,
+		ModeUnitTest:       true,
+		ModeRefactorNaming: true,
+	}
+
+	// modifyModes are the modes that can replace existing code instead of only adding to it unless the capabilities
+	// set other ones.
+	modifyModes = map[Mode]bool{
+		ModeDocument: true,
+	}
+
+	// emptySourceModes are the modes that can generate code into an empty file.
+	emptySourceModes = map[Mode]bool{
+		ModeCompletion: true,
+		ModeCode:       true,
+	}
+)
+
+// FieldError describes an invalid field, the field is named by its JSON path, e.g. "process.mode".
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// ValidationError is returned when a request is invalid, it lists every invalid field.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidRequest, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidRequest
+}
+
+func (e *ValidationError) add(field string, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (e *ValidationError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the request against DefaultCapabilities without calling the API and returns a *ValidationError
+// when it is invalid.
+func (r *CreateProcessRequest) Validate() error {
+	return r.ValidateWith(DefaultCapabilities)
+}
+
+// ValidateWith checks the request against the capabilities without calling the API and returns a *ValidationError
+// when it is invalid.
+func (r *CreateProcessRequest) ValidateWith(capabilities *CapabilityRegistry) error {
+	if r == nil {
+		return &ValidationError{Errors: []FieldError{{Field: "process", Message: "is required"}}}
+	}
+	return r.Process.ValidateWith(capabilities)
+}
+
+// Validate checks the process against DefaultCapabilities without calling the API and returns a *ValidationError
+// when it is invalid.
+func (p Process) Validate() error {
+	return p.ValidateWith(DefaultCapabilities)
+}
+
+// ValidateWith checks the process against the capabilities without calling the API and returns a *ValidationError
+// when it is invalid. Combinations of mode and language unknown to the capabilities are not checked.
+func (p Process) ValidateWith(capabilities *CapabilityRegistry) error {
+	errs := &ValidationError{}
+	p.validate("process", capabilities, errs)
+	return errs.err()
+}
+
+func (p Process) validate(path string, registry *CapabilityRegistry, errs *ValidationError) {
+	switch {
+	case p.Mode == "":
+		errs.add(path+".mode", "is required")
+	case !p.Mode.IsKnown():
+		errs.add(path+".mode", "is not a known mode %q", p.Mode)
+	}
+
+	switch {
+	case p.Language == "":
+		errs.add(path+".language", "is required")
+	case !p.Language.IsKnown():
+		errs.add(path+".language", "is not a known language %q", p.Language)
+	}
+
+	var capabilities *LanguageCapabilities
+	if registry != nil && p.Mode.IsKnown() {
+		if c, ok := registry.Capabilities(p.Language); ok {
+			capabilities = &c
+			if !c.SupportsMode(p.Mode) {
+				errs.add(path+".mode", "is not supported for language %s", p.Language)
+			}
+		}
+	}
+
+	maxSourceSize := MaxSourceSize
+	if registry != nil {
+		maxSourceSize = registry.MaxSourceSize()
+	}
+	switch {
+	case strings.TrimSpace(p.Input.Source) == "" && !emptySourceModes[p.Mode]:
+		errs.add(path+".input.source", "is empty")
+	case len(p.Input.Source) > maxSourceSize:
+		errs.add(path+".input.source", "exceeds the maximum size of %d bytes", maxSourceSize)
+	}
+
+	if p.Options != nil {
+		p.Options.validate(path+".options", p.Mode, registry, capabilities, errs)
+	}
+}
+
+func (o *Options) validate(path string, mode Mode, registry *CapabilityRegistry, capabilities *LanguageCapabilities, errs *ValidationError) {
+	if o.LanguageVersion != nil {
+		switch {
+		case strings.TrimSpace(*o.LanguageVersion) == "":
+			errs.add(path+".languageVersion", "is empty")
+		case capabilities != nil && !capabilities.SupportsVersion(*o.LanguageVersion):
+			errs.add(path+".languageVersion", "is not a supported version of %s %q", capabilities.Language, *o.LanguageVersion)
+		}
+	}
+
+	if o.Framework != nil {
+		switch {
+		case strings.TrimSpace(*o.Framework) == "":
+			errs.add(path+".framework", "is empty")
+		case mode == ModeUnitTest && capabilities != nil && !capabilities.SupportsFramework(*o.Framework):
+			errs.add(path+".framework", "is not a supported test framework for %s %q", capabilities.Language, *o.Framework)
+		}
+	}
+
+	if o.Modify != nil {
+		switch {
+		case !o.Modify.IsKnown():
+			errs.add(path+".modify", "is not a known modify value %q", *o.Modify)
+		case *o.Modify != ModifyNone && mode.IsKnown() && !supportsModify(registry, mode):
+			errs.add(path+".modify", "is not supported by mode %s", mode)
+		}
+	}
+
+	if o.CodePath != nil {
+		switch {
+		case strings.TrimSpace(*o.CodePath) == "":
+			errs.add(path+".codePath", "is empty")
+		case mode.IsKnown() && !codePathModes[mode]:
+			errs.add(path+".codePath", "is not supported by mode %s", mode)
+		}
+	}
+}
+
+func supportsModify(registry *CapabilityRegistry, mode Mode) bool {
+	if registry == nil {
+		return modifyModes[mode]
+	}
+	return registry.SupportsModify(mode)
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+
+	valid := func() Process {
+		return Process{
+			Mode:     ModeDocument,
+			Language: LanguageGo,
+			Input: Input{
+				Source: "package main",
+			},
+		}
+	}
+
+	fields := func(err error) []string {
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return nil
+		}
+		var fields []string
+		for _, fieldErr := range validationErr.Errors {
+			fields = append(fields, fieldErr.Field)
+		}
+		return fields
+	}
+
+	t.Run("Valid process passes validation", func(t *testing.T) {
+		modify := ModifyReplace
+		codePath := "main"
+		process := valid()
+		process.Options = &Options{
+			Modify:   &modify,
+			CodePath: &codePath,
+		}
+
+		if err := process.Validate(); err != nil {
+			t.Fatalf("Validation failed with an error %v", err)
+		}
+		if err := (&CreateProcessRequest{Process: process}).Validate(); err != nil {
+			t.Fatalf("Validation failed with an error %v", err)
+		}
+	})
+
+	t.Run("Empty request reports every missing field", func(t *testing.T) {
+		err := (&CreateProcessRequest{}).Validate()
+
+		if !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("Validation was expected to fail got %v", err)
+		}
+		got := strings.Join(fields(err), ",")
+		if got != "process.mode,process.language,process.input.source" {
+			t.Fatalf("Invalid fields were incorrect got %s", got)
+		}
+	})
+
+	t.Run("Unknown values and unsupported options are reported", func(t *testing.T) {
+		modify := Modify("OVERWRITE")
+		codePath := "main"
+		version := " "
+		process := valid()
+		process.Mode = ModeFixSyntax
+		process.Language = "GOLANG"
+		process.Options = &Options{
+			LanguageVersion: &version,
+			Modify:          &modify,
+			CodePath:        &codePath,
+		}
+
+		got := strings.Join(fields(process.Validate()), ",")
+		if got != "process.language,process.options.languageVersion,process.options.modify,process.options.codePath" {
+			t.Fatalf("Invalid fields were incorrect got %s", got)
+		}
+	})
+
+	t.Run("Empty source is only accepted by modes generating code", func(t *testing.T) {
+		process := valid()
+		process.Input.Source = "\n"
+
+		if got := fields(process.Validate()); len(got) != 1 || got[0] != "process.input.source" {
+			t.Fatalf("Invalid fields were incorrect got %v", got)
+		}
+
+		for _, mode := range []Mode{ModeCode, ModeCompletion} {
+			process.Mode = mode
+			if err := process.Validate(); err != nil {
+				t.Fatalf("Validation of %s failed with an error %v", mode, err)
+			}
+		}
+	})
+
+	t.Run("Modify is only supported by modes replacing code", func(t *testing.T) {
+		modify := ModifyReplace
+		process := valid()
+		process.Mode = ModeUnitTest
+		process.Options = &Options{Modify: &modify}
+
+		if got := fields(process.Validate()); len(got) != 1 || got[0] != "process.options.modify" {
+			t.Fatalf("Invalid fields were incorrect got %v", got)
+		}
+
+		modify = ModifyNone
+		if err := process.Validate(); err != nil {
+			t.Fatalf("Validation failed with an error %v", err)
+		}
+	})
+
+	t.Run("Source above the size limit is reported", func(t *testing.T) {
+		process := valid()
+		process.Input.Source = strings.Repeat("a", MaxSourceSize+1)
+
+		if got := fields(process.Validate()); len(got) != 1 || got[0] != "process.input.source" {
+			t.Fatalf("Invalid fields were incorrect got %v", got)
+		}
+	})
+
+	t.Run("Client validates requests before calling the API", func(t *testing.T) {
+		var calls int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+		}))
+		defer ts.Close()
+
+		endpoint := ts.URL
+		c := NewClient(Config{
+			ApiKey:           "ABCDE-GHIJK-LMNOP-QRSTU-1",
+			Endpoint:         &endpoint,
+			ValidateRequests: true,
+		})
+
+		_, err := c.CreateProcess(&CreateProcessRequest{Process: Process{Mode: ModeDocument}})
+		if !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("Request was expected to fail validation got %v", err)
+		}
+		if calls != 0 {
+			t.Fatalf("API was expected not to be called got %d calls", calls)
+		}
+	})
+}
@@ -0,0 +1,278 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultStateDir = ".codemaker-state"
+
+	runsDir         = "runs"
+	backupsDir      = "backups"
+	journalFile     = "journal"
+	runIdTimeFormat = "20060102T150405.000000000"
+)
+
+var ErrFileChanged = errors.New("file changed since it was read")
+
+type writeBackEntry struct {
+	Path   string      `json:"path"`
+	Backup string      `json:"backup"`
+	Hash   string      `json:"hash"`
+	Mode   os.FileMode `json:"mode"`
+}
+
+// WriteBack applies results to the source files atomically. The original of every written file is backed up and
+// recorded in the run journal of the state directory, so that the run can be undone.
+type WriteBack struct {
+	mu      sync.Mutex
+	runDir  string
+	journal *os.File
+	backups int
+}
+
+// NewWriteBack starts a new run in the state directory. The run is created with the first written file, so that a
+// run without any written file leaves nothing to undo.
+func NewWriteBack(stateDir string) (*WriteBack, error) {
+	return &WriteBack{
+		runDir: filepath.Join(stateDir, runsDir, time.Now().UTC().Format(runIdTimeFormat)),
+	}, nil
+}
+
+func (w *WriteBack) open() error {
+	if w.journal != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(w.runDir, backupsDir), 0700); err != nil {
+		return NewClientErrorWithCause("failed to create run directory", err)
+	}
+
+	journal, err := os.OpenFile(filepath.Join(w.runDir, journalFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return NewClientErrorWithCause("failed to create run journal", err)
+	}
+	w.journal = journal
+	return nil
+}
+
+// Write replaces the file content, refusing with ErrFileChanged when the file no longer matches the original it
+// was read with. The file permissions are preserved.
+func (w *WriteBack) Write(path string, original []byte, content []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return NewClientErrorWithCause("failed to resolve file path", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return NewClientErrorWithCause("failed to stat file", err)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return NewClientErrorWithCause("failed to read file", err)
+	}
+	if !bytes.Equal(current, original) {
+		return NewClientErrorWithCause(fmt.Sprintf("refusing to overwrite %s", path), ErrFileChanged)
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.backups++
+	backup := filepath.Join(w.runDir, backupsDir, fmt.Sprint(w.backups))
+	if err := os.WriteFile(backup, original, info.Mode().Perm()); err != nil {
+		return NewClientErrorWithCause("failed to back up file", err)
+	}
+
+	entry, err := json.Marshal(&writeBackEntry{
+		Path:   path,
+		Backup: backup,
+		Hash:   hash(content),
+		Mode:   info.Mode().Perm(),
+	})
+	if err != nil {
+		return NewClientErrorWithCause("failed to serialize journal entry", err)
+	}
+	if _, err := w.journal.Write(append(entry, '\n')); err != nil {
+		return NewClientErrorWithCause("failed to write journal entry", err)
+	}
+	if err := w.journal.Sync(); err != nil {
+		return NewClientErrorWithCause("failed to write journal entry", err)
+	}
+
+	return writeAtomic(path, content, info.Mode().Perm())
+}
+
+// Close closes the run journal.
+func (w *WriteBack) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.journal == nil {
+		return nil
+	}
+	return w.journal.Close()
+}
+
+// Undo restores the backups of the last run in the state directory and returns the restored paths. Files changed
+// after the run are left untouched and reported in the error. The run is removed once fully restored. Runs without
+// any written file are removed and skipped.
+func Undo(stateDir string) ([]string, error) {
+	runs, err := os.ReadDir(filepath.Join(stateDir, runsDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, NewClientError("no run to undo")
+		}
+		return nil, NewClientErrorWithCause("failed to read runs", err)
+	}
+
+	var names []string
+	for _, run := range runs {
+		if run.IsDir() {
+			names = append(names, run.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var runDir string
+	var entries []writeBackEntry
+	for i := len(names) - 1; i >= 0 && len(entries) == 0; i-- {
+		runDir = filepath.Join(stateDir, runsDir, names[i])
+		if entries, err = readJournal(filepath.Join(runDir, journalFile)); err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			if err := os.RemoveAll(runDir); err != nil {
+				return nil, NewClientErrorWithCause("failed to remove run", err)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return nil, NewClientError("no run to undo")
+	}
+
+	var restored []string
+	var failures []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if err := restore(entry); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", entry.Path, err))
+			continue
+		}
+		restored = append(restored, entry.Path)
+	}
+
+	if len(failures) > 0 {
+		return restored, NewClientError(fmt.Sprintf("failed to restore %d files: %s", len(failures), strings.Join(failures, "; ")))
+	}
+	if err := os.RemoveAll(runDir); err != nil {
+		return restored, NewClientErrorWithCause("failed to remove run", err)
+	}
+	return restored, nil
+}
+
+func readJournal(path string) ([]writeBackEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, NewClientErrorWithCause("failed to open run journal", err)
+	}
+	defer file.Close()
+
+	var entries []writeBackEntry
+	var parseErr error
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		if parseErr != nil {
+			return nil, NewClientErrorWithCause("failed to parse run journal", parseErr)
+		}
+
+		var entry writeBackEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// The last entry may be incomplete when the run crashed while writing it, before the file was written.
+			parseErr = err
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewClientErrorWithCause("failed to read run journal", err)
+	}
+	return entries, nil
+}
+
+func restore(entry writeBackEntry) error {
+	backup, err := os.ReadFile(entry.Backup)
+	if err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(entry.Path)
+	if err != nil {
+		return err
+	}
+	if hash(current) != entry.Hash {
+		if bytes.Equal(current, backup) {
+			return nil
+		}
+		return ErrFileChanged
+	}
+	return writeAtomic(entry.Path, backup, entry.Mode)
+}
+
+// writeAtomic writes the content to a temporary file in the same directory and renames it over the path.
+func writeAtomic(path string, content []byte, perm os.FileMode) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return NewClientErrorWithCause("failed to create temporary file", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return NewClientErrorWithCause("failed to write temporary file", err)
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return NewClientErrorWithCause("failed to write temporary file", err)
+	}
+	if err := temp.Close(); err != nil {
+		return NewClientErrorWithCause("failed to write temporary file", err)
+	}
+	if err := os.Chmod(temp.Name(), perm); err != nil {
+		return NewClientErrorWithCause("failed to set file permissions", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return NewClientErrorWithCause("failed to replace file", err)
+	}
+	return nil
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package client
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteBack(t *testing.T) {
+
+	t.Run("Write replaces file and preserves permissions", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "main.sh")
+		os.WriteFile(path, []byte("original"), 0755)
+		os.Chmod(path, 0750)
+
+		writeBack, err := NewWriteBack(filepath.Join(dir, DefaultStateDir))
+		if err != nil {
+			t.Fatalf("NewWriteBack failed with an error %v", err)
+		}
+		defer writeBack.Close()
+
+		if err := writeBack.Write(path, []byte("original"), []byte("updated")); err != nil {
+			t.Fatalf("Write failed with an error %v", err)
+		}
+
+		content, _ := os.ReadFile(path)
+		if string(content) != "updated" {
+			t.Fatalf("File content was incorrect got %s", content)
+		}
+		info, _ := os.Stat(path)
+		if info.Mode().Perm() != 0750 {
+			t.Fatalf("File permissions were incorrect got %v", info.Mode().Perm())
+		}
+	})
+
+	t.Run("Write refuses to overwrite changed file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "main.go")
+		os.WriteFile(path, []byte("changed"), 0644)
+
+		writeBack, err := NewWriteBack(filepath.Join(dir, DefaultStateDir))
+		if err != nil {
+			t.Fatalf("NewWriteBack failed with an error %v", err)
+		}
+		defer writeBack.Close()
+
+		err = writeBack.Write(path, []byte("original"), []byte("updated"))
+		if !errors.Is(err, ErrFileChanged) {
+			t.Fatalf("Error was expected to be ErrFileChanged got %v", err)
+		}
+
+		content, _ := os.ReadFile(path)
+		if string(content) != "changed" {
+			t.Fatalf("File was expected not to change got %s", content)
+		}
+	})
+
+	t.Run("Undo restores the last run", func(t *testing.T) {
+		dir := t.TempDir()
+		stateDir := filepath.Join(dir, DefaultStateDir)
+		path := filepath.Join(dir, "main.go")
+		os.WriteFile(path, []byte("v1"), 0644)
+
+		for _, version := range [][2]string{{"v1", "v2"}, {"v2", "v3"}} {
+			writeBack, err := NewWriteBack(stateDir)
+			if err != nil {
+				t.Fatalf("NewWriteBack failed with an error %v", err)
+			}
+			if err := writeBack.Write(path, []byte(version[0]), []byte(version[1])); err != nil {
+				t.Fatalf("Write failed with an error %v", err)
+			}
+			writeBack.Close()
+		}
+
+		restored, err := Undo(stateDir)
+		if err != nil {
+			t.Fatalf("Undo failed with an error %v", err)
+		}
+		if len(restored) != 1 {
+			t.Fatalf("Restored files were incorrect got %v", restored)
+		}
+		if content, _ := os.ReadFile(path); string(content) != "v2" {
+			t.Fatalf("File content was incorrect got %s", content)
+		}
+
+		if _, err := Undo(stateDir); err != nil {
+			t.Fatalf("Undo failed with an error %v", err)
+		}
+		if content, _ := os.ReadFile(path); string(content) != "v1" {
+			t.Fatalf("File content was incorrect got %s", content)
+		}
+
+		if _, err := Undo(stateDir); err == nil {
+			t.Fatalf("Error was expected when there is no run to undo")
+		}
+	})
+
+	t.Run("Undo leaves files changed after the run", func(t *testing.T) {
+		dir := t.TempDir()
+		stateDir := filepath.Join(dir, DefaultStateDir)
+		path := filepath.Join(dir, "main.go")
+		os.WriteFile(path, []byte("v1"), 0644)
+
+		writeBack, _ := NewWriteBack(stateDir)
+		writeBack.Write(path, []byte("v1"), []byte("v2"))
+		writeBack.Close()
+
+		os.WriteFile(path, []byte("edited"), 0644)
+
+		if _, err := Undo(stateDir); err == nil {
+			t.Fatalf("Error was expected")
+		}
+		if content, _ := os.ReadFile(path); string(content) != "edited" {
+			t.Fatalf("File was expected not to change got %s", content)
+		}
+	})
+	t.Run("Undo skips runs without written files", func(t *testing.T) {
+		dir := t.TempDir()
+		stateDir := filepath.Join(dir, DefaultStateDir)
+		path := filepath.Join(dir, "main.go")
+		os.WriteFile(path, []byte("v1"), 0644)
+
+		writeBack, _ := NewWriteBack(stateDir)
+		writeBack.Write(path, []byte("v1"), []byte("v2"))
+		writeBack.Close()
+
+		writeBack, _ = NewWriteBack(stateDir)
+		if err := writeBack.Write(path, []byte("v1"), []byte("v3")); !errors.Is(err, ErrFileChanged) {
+			t.Fatalf("Error was expected to be ErrFileChanged got %v", err)
+		}
+		writeBack.Close()
+		os.MkdirAll(filepath.Join(stateDir, runsDir, "99999999T000000.000000000", backupsDir), 0700)
+
+		restored, err := Undo(stateDir)
+		if err != nil {
+			t.Fatalf("Undo failed with an error %v", err)
+		}
+		if len(restored) != 1 {
+			t.Fatalf("Restored files were incorrect got %v", restored)
+		}
+		if content, _ := os.ReadFile(path); string(content) != "v1" {
+			t.Fatalf("File content was incorrect got %s", content)
+		}
+	})
+	t.Run("Undo ignores incomplete last journal entry", func(t *testing.T) {
+		dir := t.TempDir()
+		stateDir := filepath.Join(dir, DefaultStateDir)
+		path := filepath.Join(dir, "main.go")
+		os.WriteFile(path, []byte("v1"), 0644)
+
+		writeBack, _ := NewWriteBack(stateDir)
+		writeBack.Write(path, []byte("v1"), []byte("v2"))
+		writeBack.journal.WriteString(`{"path": "`)
+		writeBack.Close()
+
+		restored, err := Undo(stateDir)
+		if err != nil {
+			t.Fatalf("Undo failed with an error %v", err)
+		}
+		if len(restored) != 1 {
+			t.Fatalf("Restored files were incorrect got %v", restored)
+		}
+		if content, _ := os.ReadFile(path); string(content) != "v1" {
+			t.Fatalf("File content was incorrect got %s", content)
+		}
+	})
+
+	t.Run("Undo fails on corrupted journal entry", func(t *testing.T) {
+		dir := t.TempDir()
+		stateDir := filepath.Join(dir, DefaultStateDir)
+		path := filepath.Join(dir, "main.go")
+		os.WriteFile(path, []byte("v1"), 0644)
+
+		writeBack, _ := NewWriteBack(stateDir)
+		writeBack.Write(path, []byte("v1"), []byte("v2"))
+		writeBack.journal.WriteString("corrupted\n")
+		writeBack.Write(path, []byte("v2"), []byte("v3"))
+		writeBack.Close()
+
+		if _, err := Undo(stateDir); err == nil {
+			t.Fatalf("Error was expected")
+		}
+	})
+}
@@ -0,0 +1,404 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+// Command codemaker processes source files with the CodeMaker AI APIs.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+const minLanguageConfidence = 0.5
+
+var modes = map[string]client.Mode{
+	"document":        client.ModeDocument,
+	"unit-test":       client.ModeUnitTest,
+	"fix-syntax":      client.ModeFixSyntax,
+	"refactor-naming": client.ModeRefactorNaming,
+	"migrate-syntax":  client.ModeMigrateSyntax,
+	"code":            client.ModeCode,
+	"edit":            client.ModeEditCode,
+	"complete":        client.ModeCompletion,
+}
+
+type options struct {
+	mode            client.Mode
+	language        client.Language
+	languageVersion string
+	framework       string
+	modify          client.Modify
+	codePath        string
+	inPlace         bool
+	outputDir       string
+	diff            bool
+	diffContext     int
+	ignoreSpace     bool
+	stateDir        string
+	profile         string
+	workers         int
+	verbose         bool
+	validate        bool
+	compress        bool
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+
+	if args[0] == "undo" {
+		return undo(args[1:], stdout, stderr)
+	}
+	if args[0] == "capabilities" {
+		return capabilities(args[1:], stdout, stderr)
+	}
+
+	mode, ok := modes[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	opts := options{mode: mode}
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Func("language", "source language, detected from the file extension when not set", func(value string) (err error) {
+		opts.language, err = client.ParseLanguage(value)
+		return err
+	})
+	flags.StringVar(&opts.languageVersion, "language-version", "", "target language version")
+	flags.StringVar(&opts.framework, "framework", "", "target framework, e.g. the unit test framework")
+	flags.Func("modify", "modification mode, NONE or REPLACE", func(value string) (err error) {
+		opts.modify, err = client.ParseModify(value)
+		return err
+	})
+	flags.StringVar(&opts.codePath, "code-path", "", "path of the code element to process")
+	flags.BoolVar(&opts.inPlace, "in-place", false, "write the results back to the source files")
+	flags.StringVar(&opts.outputDir, "output-dir", "", "write the results to the directory")
+	flags.BoolVar(&opts.diff, "diff", false, "print a unified diff between the source and the result")
+	flags.IntVar(&opts.diffContext, "diff-context", 3, "number of unchanged lines around each change in the diff")
+	flags.BoolVar(&opts.ignoreSpace, "ignore-whitespace", false, "ignore whitespace changes in the diff")
+	flags.StringVar(&opts.stateDir, "state-dir", client.DefaultStateDir, "directory keeping the backups for undo")
+	flags.StringVar(&opts.profile, "profile", "", "config file profile")
+	flags.IntVar(&opts.workers, "workers", 4, "number of files processed concurrently")
+	flags.BoolVar(&opts.verbose, "verbose", false, "log the API requests to stderr")
+	flags.BoolVar(&opts.validate, "validate", false, "validate the requests before sending them")
+	flags.BoolVar(&opts.compress, "compress", false, "gzip compress large requests")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if opts.inPlace && opts.outputDir != "" {
+		fmt.Fprintln(stderr, "-in-place and -output-dir cannot be used together")
+		return 2
+	}
+
+	files, err := expand(flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "no files to process")
+		return 2
+	}
+
+	loaded, err := client.LoadConfig(client.LoadConfigOptions{
+		Profile: opts.profile,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	loaded.Config.ValidateRequests = opts.validate
+	loaded.Config.CompressRequests = opts.compress
+	if opts.verbose {
+		loaded.Config.Logger = slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	if err := process(ctx, client.NewClient(loaded.Config), opts, files, stdout, stderr); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) {
+	commands := make([]string, 0, len(modes))
+	for command := range modes {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+
+	fmt.Fprintln(w, "Usage: codemaker <command> [flags] <files or globs...>")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, command := range commands {
+		fmt.Fprintf(w, "  %s\n", command)
+	}
+	fmt.Fprintln(w, "  undo")
+	fmt.Fprintln(w, "  capabilities")
+}
+
+func undo(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("undo", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	stateDir := flags.String("state-dir", client.DefaultStateDir, "directory keeping the backups for undo")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	restored, err := client.Undo(*stateDir)
+	for _, path := range restored {
+		fmt.Fprintf(stdout, "restored %s\n", path)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func capabilities(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("capabilities", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var mode client.Mode
+	flags.Func("mode", "list only the languages supporting the mode", func(value string) (err error) {
+		mode, err = client.ParseMode(value)
+		return err
+	})
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	languages := client.DefaultCapabilities.Languages()
+	if mode != "" {
+		languages = client.DefaultCapabilities.LanguagesForMode(mode)
+	}
+
+	for _, language := range languages {
+		c, _ := client.DefaultCapabilities.Capabilities(language)
+		modes := make([]string, len(c.Modes))
+		for i, m := range c.Modes {
+			modes[i] = m.String()
+		}
+		fmt.Fprintln(stdout, language)
+		fmt.Fprintf(stdout, "  modes: %s\n", join(modes))
+		fmt.Fprintf(stdout, "  versions: %s\n", join(c.Versions))
+		fmt.Fprintf(stdout, "  frameworks: %s\n", join(c.Frameworks))
+	}
+	return 0
+}
+
+func join(values []string) string {
+	if len(values) == 0 {
+		return "any"
+	}
+	return strings.Join(values, ", ")
+}
+
+// expand resolves the glob patterns into a sorted list of unique files.
+func expand(patterns []string) ([]string, error) {
+	seen := map[string]bool{}
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", pattern)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || info.IsDir() || seen[match] {
+				continue
+			}
+			seen[match] = true
+			files = append(files, match)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func process(ctx context.Context, c client.Client, opts options, files []string, stdout io.Writer, stderr io.Writer) error {
+	var targets map[string]string
+	if opts.outputDir != "" {
+		var err error
+		if targets, err = outputTargets(opts.outputDir, files); err != nil {
+			return err
+		}
+	}
+
+	jobs := make([]client.BatchJob, 0, len(files))
+	for _, file := range files {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		language := opts.language
+		if language == "" {
+			detection := client.DetectLanguage(file, source)
+			if !detection.Supported() || detection.Confidence < minLanguageConfidence {
+				return fmt.Errorf("cannot detect language of %s, use -language", file)
+			}
+			language = detection.Language
+		}
+
+		jobs = append(jobs, client.BatchJob{
+			Id: file,
+			Process: client.Process{
+				Mode:     opts.mode,
+				Language: language,
+				Input: client.Input{
+					Source: string(source),
+				},
+				Options: processOptions(opts),
+			},
+		})
+	}
+
+	processor := client.NewBatchProcessor(c, client.BatchConfig{
+		Workers: opts.workers,
+	})
+
+	results := map[string]client.BatchResult{}
+	for result := range processor.Process(ctx, jobs) {
+		results[result.JobId] = result
+	}
+
+	var writeBack *client.WriteBack
+	if opts.inPlace {
+		var err error
+		if writeBack, err = client.NewWriteBack(opts.stateDir); err != nil {
+			return err
+		}
+		defer writeBack.Close()
+	}
+
+	var failed int
+	for i, file := range files {
+		result := results[file]
+		if result.Err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", file, result.Err)
+			failed++
+			continue
+		}
+		if opts.diff {
+			printDiff(opts, file, jobs[i].Process.Input.Source, result.Output.Source, stdout)
+			if !opts.inPlace && opts.outputDir == "" {
+				continue
+			}
+		}
+		if err := write(opts, writeBack, file, targets[file], jobs[i].Process.Input.Source, result.Output.Source, stdout, len(files) > 1); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", file, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(files))
+	}
+	return nil
+}
+
+func processOptions(opts options) *client.Options {
+	if opts.languageVersion == "" && opts.framework == "" && opts.modify == "" && opts.codePath == "" {
+		return nil
+	}
+
+	options := &client.Options{}
+	if opts.languageVersion != "" {
+		options.LanguageVersion = &opts.languageVersion
+	}
+	if opts.framework != "" {
+		options.Framework = &opts.framework
+	}
+	if opts.modify != "" {
+		options.Modify = &opts.modify
+	}
+	if opts.codePath != "" {
+		options.CodePath = &opts.codePath
+	}
+	return options
+}
+
+func printDiff(opts options, file string, input string, output string, stdout io.Writer) {
+	path := filepath.ToSlash(file)
+	diff := client.DiffSources(input, output, client.DiffConfig{
+		Context:          &opts.diffContext,
+		IgnoreWhitespace: opts.ignoreSpace,
+		FromName:         "a/" + path,
+		ToName:           "b/" + path,
+	})
+	io.WriteString(stdout, diff.Unified())
+}
+
+// outputTargets maps the files to their paths in the output directory, keeping their paths relative to the working
+// directory. Files outside the working directory are rejected, so that no result is written outside the output
+// directory.
+func outputTargets(outputDir string, files []string) (map[string]string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]string, len(files))
+	sources := map[string]string{}
+	for _, file := range files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(wd, path)
+		if err != nil || !filepath.IsLocal(rel) {
+			return nil, fmt.Errorf("%s is outside the working directory and cannot be written to -output-dir", file)
+		}
+
+		target := filepath.Join(outputDir, rel)
+		if other, ok := sources[target]; ok {
+			return nil, fmt.Errorf("%s and %s would both be written to %s", other, file, target)
+		}
+		sources[target] = file
+		targets[file] = target
+	}
+	return targets, nil
+}
+
+func write(opts options, writeBack *client.WriteBack, file string, target string, original string, source string, stdout io.Writer, header bool) error {
+	switch {
+	case opts.inPlace:
+		return writeBack.Write(file, []byte(original), []byte(source))
+	case opts.outputDir != "":
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, []byte(source), 0644)
+	default:
+		if header {
+			fmt.Fprintf(stdout, "==> %s <==\n", file)
+		}
+		_, err := io.WriteString(stdout, source)
+		return err
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+	"github.com/codemakerai/codemaker-sdk-go/codemakertest"
+)
+
+func server(t *testing.T) *codemakertest.Server {
+	s := codemakertest.NewServer()
+	s.Backend.SetLifecycle(func(process client.Process) codemakertest.Lifecycle {
+		return codemakertest.Lifecycle{
+			Statuses: []client.Status{client.StatusCompleted},
+			Output: client.Output{
+				Source: "// documented\n" + process.Input.Source,
+			},
+		}
+	})
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CODEMAKER_CONFIG_FILE", "")
+	t.Setenv("CODEMAKER_PROFILE", "")
+	t.Setenv("CODEMAKER_API_KEY", "key")
+	t.Setenv("CODEMAKER_ENDPOINT", s.URL)
+	return s
+}
+
+func sourceFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write file %v", err)
+	}
+	return path
+}
+
+func chdir(t *testing.T, dir string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change working directory %v", err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+}
+
+func TestRun(t *testing.T) {
+
+	t.Run("Unknown command results in usage", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := run(context.Background(), []string{"unknown"}, &stdout, &stderr)
+
+		if code != 2 {
+			t.Fatalf("Exit code was incorrect got %d", code)
+		}
+		if !strings.Contains(stderr.String(), "Usage") {
+			t.Fatalf("Usage was expected got %s", stderr.String())
+		}
+	})
+
+	t.Run("Capabilities command lists languages supporting the mode", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := run(context.Background(), []string{"capabilities", "-mode", "migrate-syntax"}, &stdout, &stderr)
+
+		if code != 0 {
+			t.Fatalf("Exit code was incorrect got %d: %s", code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), "JAVA\n") || !strings.Contains(stdout.String(), "versions: any\n") {
+			t.Fatalf("Output was incorrect got %s", stdout.String())
+		}
+	})
+
+	t.Run("Document command writes result to stdout", func(t *testing.T) {
+		s := server(t)
+		defer s.Close()
+
+		file := sourceFile(t, t.TempDir(), "main.go", "package main\n")
+		var stdout, stderr bytes.Buffer
+
+		code := run(context.Background(), []string{"document", "-framework", "testing", file}, &stdout, &stderr)
+
+		if code != 0 {
+			t.Fatalf("Exit code was incorrect got %d: %s", code, stderr.String())
+		}
+		if stdout.String() != "// documented\npackage main\n" {
+			t.Fatalf("Output was incorrect got %s", stdout.String())
+		}
+
+		processes := s.Backend.Processes()
+		if len(processes) != 1 || processes[0].Mode != client.ModeDocument || processes[0].Language != client.LanguageGo {
+			t.Fatalf("Processes were incorrect got %v", processes)
+		}
+		if processes[0].Options == nil || *processes[0].Options.Framework != "testing" {
+			t.Fatalf("Process options were incorrect got %v", processes[0].Options)
+		}
+	})
+
+	t.Run("Document command prints diff", func(t *testing.T) {
+		s := server(t)
+		defer s.Close()
+
+		dir := t.TempDir()
+		file := sourceFile(t, dir, "main.go", "package main\n")
+		var stdout, stderr bytes.Buffer
+
+		code := run(context.Background(), []string{"document", "-diff", file}, &stdout, &stderr)
+
+		if code != 0 {
+			t.Fatalf("Exit code was incorrect got %d: %s", code, stderr.String())
+		}
+		path := filepath.ToSlash(file)
+		expected := "--- a/" + path + "\n+++ b/" + path + "\n@@ -1 +1,2 @@\n+// documented\n package main\n"
+		if stdout.String() != expected {
+			t.Fatalf("Diff was incorrect got\n%s", stdout.String())
+		}
+	})
+
+	t.Run("Unit test command writes results in place", func(t *testing.T) {
+		s := server(t)
+		defer s.Close()
+
+		dir := t.TempDir()
+		sourceFile(t, dir, "a.java", "class A {}\n")
+		sourceFile(t, dir, "b.java", "class B {}\n")
+		var stdout, stderr bytes.Buffer
+
+		stateDir := filepath.Join(t.TempDir(), "state")
+
+		code := run(context.Background(), []string{"unit-test", "-in-place", "-state-dir", stateDir, filepath.Join(dir, "*.java")}, &stdout, &stderr)
+
+		if code != 0 {
+			t.Fatalf("Exit code was incorrect got %d: %s", code, stderr.String())
+		}
+		for _, name := range []string{"a.java", "b.java"} {
+			content, _ := os.ReadFile(filepath.Join(dir, name))
+			if !strings.HasPrefix(string(content), "// documented\n") {
+				t.Fatalf("File %s was not updated got %s", name, content)
+			}
+		}
+
+		code = run(context.Background(), []string{"undo", "-state-dir", stateDir}, &stdout, &stderr)
+
+		if code != 0 {
+			t.Fatalf("Exit code was incorrect got %d: %s", code, stderr.String())
+		}
+		if content, _ := os.ReadFile(filepath.Join(dir, "a.java")); string(content) != "class A {}\n" {
+			t.Fatalf("File was not restored got %s", content)
+		}
+	})
+
+	t.Run("Document command writes results to output directory", func(t *testing.T) {
+		s := server(t)
+		defer s.Close()
+
+		chdir(t, t.TempDir())
+		os.Mkdir("src", 0755)
+		sourceFile(t, "src", "main.go", "package main\n")
+		outputDir := filepath.Join(t.TempDir(), "out")
+		var stdout, stderr bytes.Buffer
+
+		code := run(context.Background(), []string{"document", "-output-dir", outputDir, filepath.Join("src", "main.go")}, &stdout, &stderr)
+
+		if code != 0 {
+			t.Fatalf("Exit code was incorrect got %d: %s", code, stderr.String())
+		}
+		if content, _ := os.ReadFile(filepath.Join(outputDir, "src", "main.go")); string(content) != "// documented\npackage main\n" {
+			t.Fatalf("Output was incorrect got %s", content)
+		}
+	})
+
+	t.Run("Output directory rejects files outside working directory", func(t *testing.T) {
+		s := server(t)
+		defer s.Close()
+
+		dir := t.TempDir()
+		sourceFile(t, dir, "main.go", "package main\n")
+		os.Mkdir(filepath.Join(dir, "work"), 0755)
+		chdir(t, filepath.Join(dir, "work"))
+		outputDir := filepath.Join(t.TempDir(), "out")
+		var stdout, stderr bytes.Buffer
+
+		code := run(context.Background(), []string{"document", "-output-dir", outputDir, "../main.go"}, &stdout, &stderr)
+
+		if code != 1 || !strings.Contains(stderr.String(), "outside the working directory") {
+			t.Fatalf("Exit code was incorrect got %d: %s", code, stderr.String())
+		}
+		if len(s.Backend.Processes()) != 0 {
+			t.Fatalf("Files were expected not to be processed")
+		}
+	})
+
+	t.Run("Code command with validation accepts empty file", func(t *testing.T) {
+		s := server(t)
+		defer s.Close()
+
+		file := sourceFile(t, t.TempDir(), "main.go", "")
+		var stdout, stderr bytes.Buffer
+
+		code := run(context.Background(), []string{"code", "-validate", "-language", "go", file}, &stdout, &stderr)
+
+		if code != 0 {
+			t.Fatalf("Exit code was incorrect got %d: %s", code, stderr.String())
+		}
+		if len(s.Backend.Processes()) != 1 {
+			t.Fatalf("File was expected to be processed")
+		}
+	})
+
+	t.Run("Unknown language results in error", func(t *testing.T) {
+		s := server(t)
+		defer s.Close()
+
+		file := sourceFile(t, t.TempDir(), "notes.txt", "notes")
+		var stdout, stderr bytes.Buffer
+
+		code := run(context.Background(), []string{"document", file}, &stdout, &stderr)
+
+		if code != 1 {
+			t.Fatalf("Exit code was incorrect got %d", code)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+module github.com/codemakerai/codemaker-sdk-go/codemakerotel
+
+go 1.21
+
+require (
+	github.com/codemakerai/codemaker-sdk-go v0.0.18
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/sdk v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.1 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	go.opentelemetry.io/otel/metric v1.24.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+)
+
+// The adapter requires the first SDK release with client.Tracer. The SDK is tagged v0.0.18 before the adapter is tagged
+// codemakerotel/v0.0.18, so the module is not released until then. The replace directive builds the adapter against
+// the SDK in this repository and is ignored by the consumers of the module, who get the SDK version required above.
+replace github.com/codemakerai/codemaker-sdk-go => ../
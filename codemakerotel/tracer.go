@@ -0,0 +1,98 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+// Package codemakerotel adapts OpenTelemetry tracing to the client.Tracer interface.
+package codemakerotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/codemakerai/codemaker-sdk-go"
+
+type Config struct {
+	// TracerProvider creates the tracer, defaults to the global tracer provider.
+	TracerProvider trace.TracerProvider
+	// Propagator injects the trace headers, defaults to the global propagator.
+	Propagator propagation.TextMapPropagator
+}
+
+type tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTracer creates a client.Tracer creating OpenTelemetry client spans.
+func NewTracer(config Config) client.Tracer {
+	provider := config.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	propagator := config.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	return &tracer{
+		tracer:     provider.Tracer(instrumentationName, trace.WithInstrumentationVersion(client.Version)),
+		propagator: propagator,
+	}
+}
+
+func (t *tracer) Start(ctx context.Context, name string, attributes ...client.Attribute) (context.Context, client.Span) {
+	ctx, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(convert(attributes)...))
+	return ctx, &otelSpan{span: span}
+}
+
+func (t *tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) SetAttributes(attributes ...client.Attribute) {
+	s.span.SetAttributes(convert(attributes)...)
+}
+
+func (s *otelSpan) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *otelSpan) End() {
+	s.span.End()
+}
+
+func convert(attributes []client.Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		switch value := a.Value.(type) {
+		case string:
+			converted = append(converted, attribute.String(a.Key, value))
+		case int:
+			converted = append(converted, attribute.Int(a.Key, value))
+		case int64:
+			converted = append(converted, attribute.Int64(a.Key, value))
+		case float64:
+			converted = append(converted, attribute.Float64(a.Key, value))
+		case bool:
+			converted = append(converted, attribute.Bool(a.Key, value))
+		default:
+			converted = append(converted, attribute.String(a.Key, fmt.Sprint(value)))
+		}
+	}
+	return converted
+}
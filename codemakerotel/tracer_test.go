@@ -0,0 +1,106 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package codemakerotel
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+	"github.com/codemakerai/codemaker-sdk-go/codemakertest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+
+	t.Run("Tracer creates OpenTelemetry spans and propagates the trace", func(t *testing.T) {
+		s := codemakertest.NewServer()
+		defer s.Close()
+
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+		var traceparent string
+		c := s.ClientWithConfig(client.Config{
+			Tracer: NewTracer(Config{
+				TracerProvider: provider,
+				Propagator:     propagation.TraceContext{},
+			}),
+			Middlewares: []client.Middleware{
+				func(next client.Handler) client.Handler {
+					return func(call *client.Call) (*http.Response, error) {
+						traceparent = call.HttpRequest.Header.Get("traceparent")
+						return next(call)
+					}
+				},
+			},
+		})
+
+		ctx, root := provider.Tracer("test").Start(context.Background(), "root")
+		_, err := c.CreateProcessWithContext(ctx, &client.CreateProcessRequest{
+			Process: client.Process{
+				Mode:     client.ModeDocument,
+				Language: client.LanguageGo,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+		root.End()
+
+		var span sdktrace.ReadOnlySpan
+		for _, ended := range recorder.Ended() {
+			if ended.Name() == "codemaker."+client.OperationCreateProcess {
+				span = ended
+			}
+		}
+		if span == nil {
+			t.Fatalf("CreateProcess span was expected to be recorded")
+		}
+		if span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Fatalf("CreateProcess span was expected to be a child of the root span")
+		}
+		if !hasAttribute(span, attribute.String(client.AttributeMode, client.ModeDocument.String())) ||
+			!hasAttribute(span, attribute.Int(client.AttributeHttpStatusCode, http.StatusCreated)) {
+			t.Fatalf("CreateProcess span attributes were incorrect got %v", span.Attributes())
+		}
+		if traceparent == "" || traceparent[3:35] != root.SpanContext().TraceID().String() {
+			t.Fatalf("Trace header was incorrect got %s", traceparent)
+		}
+	})
+
+	t.Run("Tracer records errors", func(t *testing.T) {
+		s := codemakertest.NewServer()
+		defer s.Close()
+
+		recorder := tracetest.NewSpanRecorder()
+		c := s.ClientWithConfig(client.Config{
+			Tracer: NewTracer(Config{
+				TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+			}),
+		})
+
+		if _, err := c.GetProcessStatus(&client.GetProcessStatusRequest{Id: "unknown"}); err == nil {
+			t.Fatalf("Request was expected to fail")
+		}
+
+		spans := recorder.Ended()
+		if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+			t.Fatalf("Span was expected to record the error got %v", spans)
+		}
+	})
+}
+
+func hasAttribute(span sdktrace.ReadOnlySpan, expected attribute.KeyValue) bool {
+	for _, kv := range span.Attributes() {
+		if kv == expected {
+			return true
+		}
+	}
+	return false
+}
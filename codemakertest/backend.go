@@ -0,0 +1,331 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+// Package codemakertest provides a fake client and a fake CodeMaker API server for tests.
+package codemakertest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+const (
+	ErrorCodeBadRequest   = "BAD_REQUEST"
+	ErrorCodeNotFound     = "NOT_FOUND"
+	ErrorCodeRateLimited  = "RATE_LIMITED"
+	ErrorCodeNotCompleted = "PROCESS_NOT_COMPLETED"
+)
+
+// Lifecycle describes how a fake process behaves.
+type Lifecycle struct {
+	// Statuses are reported by consecutive status checks, the last status is repeated.
+	Statuses []client.Status
+	// Output is returned once the process has completed.
+	Output client.Output
+}
+
+// DefaultLifecycle reports the process in progress once, then completed with the input source as output.
+func DefaultLifecycle(process client.Process) Lifecycle {
+	return Lifecycle{
+		Statuses: []client.Status{client.StatusInProgress, client.StatusCompleted},
+		Output: client.Output{
+			Source: process.Input.Source,
+		},
+	}
+}
+
+type process struct {
+	lifecycle Lifecycle
+	polls     int
+	status    client.Status
+}
+
+type failure struct {
+	operation string
+	err       *client.APIError
+}
+
+// Backend simulates the process lifecycle of the CodeMaker API. It is safe for concurrent use.
+type Backend struct {
+	mu          sync.Mutex
+	lifecycle   func(process client.Process) Lifecycle
+	latency     time.Duration
+	rateLimit   int
+	rateWindow  time.Duration
+	windowStart time.Time
+	windowCount int
+	failures    []failure
+	processes   map[string]*process
+	created     []client.Process
+	cancelled   []string
+	nextId      int
+	nextRequest int64
+}
+
+func NewBackend() *Backend {
+	return &Backend{
+		lifecycle: DefaultLifecycle,
+		processes: map[string]*process{},
+	}
+}
+
+// SetLifecycle sets the function deciding the lifecycle of every newly created process.
+func (b *Backend) SetLifecycle(lifecycle func(process client.Process) Lifecycle) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lifecycle = lifecycle
+}
+
+// SetLatency delays every operation by the given duration.
+func (b *Backend) SetLatency(latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.latency = latency
+}
+
+// SetRateLimit allows at most requests operations per window, rejecting the others as rate limited. A requests or
+// window that is not positive disables the rate limiting.
+func (b *Backend) SetRateLimit(requests int, window time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rateLimit = requests
+	b.rateWindow = window
+	b.windowStart = time.Time{}
+	b.windowCount = 0
+}
+
+// FailNext makes the next call of the operation, e.g. client.OperationCreateProcess, fail with the given status
+// and error code.
+func (b *Backend) FailNext(operation string, statusCode int, code string, message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = append(b.failures, failure{
+		operation: operation,
+		err: &client.APIError{
+			StatusCode: statusCode,
+			Code:       code,
+			Message:    message,
+		},
+	})
+}
+
+// Processes returns the payloads of all created processes in the order they were received.
+func (b *Backend) Processes() []client.Process {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]client.Process(nil), b.created...)
+}
+
+// Cancelled returns the ids of all cancelled processes.
+func (b *Backend) Cancelled() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]string(nil), b.cancelled...)
+}
+
+// Status returns the current status of the process.
+func (b *Backend) Status(id string) (client.Status, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p, ok := b.processes[id]
+	if !ok {
+		return "", false
+	}
+	return p.status, true
+}
+
+func (b *Backend) CreateProcess(ctx context.Context, request *client.CreateProcessRequest) (*client.CreateProcessResponse, error) {
+	if err := b.begin(ctx, client.OperationCreateProcess); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if request == nil {
+		request = &client.CreateProcessRequest{}
+	}
+
+	b.nextId++
+	id := fmt.Sprintf("process-%d", b.nextId)
+	lifecycle := b.lifecycle(request.Process)
+	if len(lifecycle.Statuses) == 0 {
+		lifecycle.Statuses = []client.Status{client.StatusCompleted}
+	}
+
+	b.processes[id] = &process{
+		lifecycle: lifecycle,
+		status:    client.StatusInProgress,
+	}
+	b.created = append(b.created, request.Process)
+
+	return &client.CreateProcessResponse{
+		Id: id,
+	}, nil
+}
+
+func (b *Backend) GetProcessStatus(ctx context.Context, request *client.GetProcessStatusRequest) (*client.GetProcessStatusResponse, error) {
+	if err := b.begin(ctx, client.OperationGetProcessStatus); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if request == nil {
+		request = &client.GetProcessStatusRequest{}
+	}
+
+	p, err := b.process(request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.status == client.StatusInProgress {
+		i := p.polls
+		if i >= len(p.lifecycle.Statuses) {
+			i = len(p.lifecycle.Statuses) - 1
+		}
+		p.status = p.lifecycle.Statuses[i]
+		p.polls++
+	}
+
+	return &client.GetProcessStatusResponse{
+		Status: p.status,
+	}, nil
+}
+
+func (b *Backend) GetProcessOutput(ctx context.Context, request *client.GetProcessOutputRequest) (*client.GetProcessOutputResponse, error) {
+	if err := b.begin(ctx, client.OperationGetProcessOutput); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if request == nil {
+		request = &client.GetProcessOutputRequest{}
+	}
+
+	p, err := b.process(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if p.status != client.StatusCompleted {
+		return nil, b.error(http.StatusBadRequest, ErrorCodeNotCompleted, "Process has not completed.")
+	}
+
+	return &client.GetProcessOutputResponse{
+		Output: p.lifecycle.Output,
+	}, nil
+}
+
+func (b *Backend) CancelProcess(ctx context.Context, request *client.CancelProcessRequest) (*client.CancelProcessResponse, error) {
+	if err := b.begin(ctx, client.OperationCancelProcess); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if request == nil {
+		request = &client.CancelProcessRequest{}
+	}
+
+	p, err := b.process(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if p.status == client.StatusInProgress {
+		p.status = client.StatusCancelled
+	}
+	b.cancelled = append(b.cancelled, request.Id)
+
+	return &client.CancelProcessResponse{}, nil
+}
+
+func (b *Backend) begin(ctx context.Context, operation string) error {
+	b.mu.Lock()
+	latency := b.latency
+	b.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rateLimit > 0 && b.rateWindow > 0 {
+		now := time.Now()
+		if now.Sub(b.windowStart) >= b.rateWindow {
+			b.windowStart = now
+			b.windowCount = 0
+		}
+		if b.windowCount >= b.rateLimit {
+			return b.error(http.StatusTooManyRequests, ErrorCodeRateLimited, "Too many requests.")
+		}
+		b.windowCount++
+	}
+
+	for i, f := range b.failures {
+		if f.operation == operation {
+			b.failures = append(b.failures[:i], b.failures[i+1:]...)
+			err := *f.err
+			err.RequestId = b.requestId()
+			return &err
+		}
+	}
+	return nil
+}
+
+func (b *Backend) process(id string) (*process, error) {
+	p, ok := b.processes[id]
+	if !ok {
+		return nil, b.error(http.StatusNotFound, ErrorCodeNotFound, "Process not found.")
+	}
+	return p, nil
+}
+
+func (b *Backend) error(statusCode int, code string, message string) *client.APIError {
+	return &client.APIError{
+		StatusCode: statusCode,
+		RequestId:  b.requestId(),
+		Code:       code,
+		Message:    message,
+	}
+}
+
+func (b *Backend) requestId() string {
+	return fmt.Sprintf("request-%d", atomic.AddInt64(&b.nextRequest, 1))
+}
+
+// retryAfter returns the time until the current rate limit window ends.
+func (b *Backend) retryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return time.Until(b.windowStart.Add(b.rateWindow))
+}
@@ -0,0 +1,229 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package codemakertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+const (
+	headerAuthorization = "Authorization"
+	redacted            = "REDACTED"
+)
+
+var ErrCassetteUnmatched = errors.New("no recorded interaction matches the request")
+
+type CassetteMode int
+
+const (
+	// CassetteRecord sends the requests and records the interactions.
+	CassetteRecord CassetteMode = iota
+	// CassetteReplay serves the recorded interactions without sending any request.
+	CassetteReplay
+)
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Headers http.Header     `json:"headers"`
+	Body    json.RawMessage `json:"body"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers"`
+	Body       string      `json:"body"`
+}
+
+// Cassette records the HTTP interactions of a client to a file and replays them. Requests are matched by method,
+// path and normalized body, each recorded interaction is replayed once in the recorded order.
+type Cassette struct {
+	mu           sync.Mutex
+	path         string
+	mode         CassetteMode
+	interactions []Interaction
+	replayed     []bool
+	unmatched    []string
+}
+
+// NewCassette creates a cassette stored at path. In CassetteReplay mode the interactions are loaded from the file.
+func NewCassette(path string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{
+		path: path,
+		mode: mode,
+	}
+	if mode != CassetteReplay {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, client.NewClientErrorWithCause("failed to read cassette", err)
+	}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, client.NewClientErrorWithCause("failed to parse cassette", err)
+	}
+	for i := range c.interactions {
+		body := &bytes.Buffer{}
+		if err := json.Compact(body, c.interactions[i].Request.Body); err == nil {
+			c.interactions[i].Request.Body = body.Bytes()
+		}
+	}
+	c.replayed = make([]bool, len(c.interactions))
+	return c, nil
+}
+
+// Middleware returns the client.Middleware recording or replaying the interactions.
+func (c *Cassette) Middleware() client.Middleware {
+	return func(next client.Handler) client.Handler {
+		return func(call *client.Call) (*http.Response, error) {
+			request, err := c.recordRequest(call)
+			if err != nil {
+				return nil, err
+			}
+			if c.mode == CassetteReplay {
+				return c.replay(call, request)
+			}
+			return c.record(call, request, next)
+		}
+	}
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return client.NewClientErrorWithCause("failed to serialize cassette", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return client.NewClientErrorWithCause("failed to write cassette", err)
+	}
+	return nil
+}
+
+// Interactions returns the recorded interactions.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Unmatched returns the requests that could not be replayed.
+func (c *Cassette) Unmatched() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.unmatched...)
+}
+
+func (c *Cassette) record(call *client.Call, request RecordedRequest, next client.Handler) (*http.Response, error) {
+	resp, err := next(call)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, Interaction{
+		Request: request,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    resp.Header.Clone(),
+			Body:       string(body),
+		},
+	})
+	return resp, nil
+}
+
+func (c *Cassette) replay(call *client.Call, request RecordedRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.interactions {
+		if c.replayed[i] || !matches(interaction.Request, request) {
+			continue
+		}
+		c.replayed[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Headers.Clone(),
+			Body:          io.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       call.HttpRequest,
+		}, nil
+	}
+
+	description := fmt.Sprintf("%s %s %s", request.Method, request.Path, request.Body)
+	c.unmatched = append(c.unmatched, description)
+	return nil, fmt.Errorf("%w: %s", ErrCassetteUnmatched, description)
+}
+
+func (c *Cassette) recordRequest(call *client.Call) (RecordedRequest, error) {
+	body, err := normalizeBody(call)
+	if err != nil {
+		return RecordedRequest{}, err
+	}
+
+	headers := call.HttpRequest.Header.Clone()
+	if headers.Get(headerAuthorization) != "" {
+		headers.Set(headerAuthorization, redacted)
+	}
+
+	return RecordedRequest{
+		Method:  call.HttpRequest.Method,
+		Path:    call.HttpRequest.URL.Path,
+		Headers: headers,
+		Body:    body,
+	}, nil
+}
+
+// normalizeBody serializes the request with sorted keys and no insignificant whitespace.
+func normalizeBody(call *client.Call) (json.RawMessage, error) {
+	data, err := json.Marshal(call.Request)
+	if err != nil {
+		return nil, client.NewClientErrorWithCause("failed to serialize request payload", err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, client.NewClientErrorWithCause("failed to parse request payload", err)
+	}
+	return json.Marshal(value)
+}
+
+func matches(recorded RecordedRequest, request RecordedRequest) bool {
+	return recorded.Method == request.Method &&
+		recorded.Path == request.Path &&
+		bytes.Equal(recorded.Body, request.Body)
+}
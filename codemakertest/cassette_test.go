@@ -0,0 +1,95 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package codemakertest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+func TestCassette(t *testing.T) {
+
+	request := &client.CreateProcessRequest{
+		Process: client.Process{
+			Mode:     client.ModeDocument,
+			Language: client.LanguageGo,
+			Input: client.Input{
+				Source: "package main",
+			},
+		},
+	}
+
+	t.Run("Cassette records and replays interactions", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cassette.json")
+
+		s := NewServer()
+		recorder, err := NewCassette(path, CassetteRecord)
+		if err != nil {
+			t.Fatalf("NewCassette failed with an error %v", err)
+		}
+
+		recorded, err := client.RunProcess(context.Background(), s.ClientWithConfig(client.Config{
+			ApiKey:      "secret-api-key",
+			Middlewares: []client.Middleware{recorder.Middleware()},
+		}), request, pollConfig())
+		s.Close()
+		if err != nil {
+			t.Fatalf("RunProcess failed with an error %v", err)
+		}
+		if err := recorder.Save(); err != nil {
+			t.Fatalf("Save failed with an error %v", err)
+		}
+
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), "secret-api-key") {
+			t.Fatalf("Cassette was expected not to contain the api key")
+		}
+
+		player, err := NewCassette(path, CassetteReplay)
+		if err != nil {
+			t.Fatalf("NewCassette failed with an error %v", err)
+		}
+
+		endpoint := s.URL
+		replayed, err := client.RunProcess(context.Background(), client.NewClient(client.Config{
+			Endpoint:    &endpoint,
+			Middlewares: []client.Middleware{player.Middleware()},
+		}), request, pollConfig())
+		if err != nil {
+			t.Fatalf("RunProcess failed with an error %v", err)
+		}
+
+		if replayed.Source != recorded.Source {
+			t.Fatalf("Replayed output was incorrect got %s", replayed.Source)
+		}
+	})
+
+	t.Run("Cassette fails on unmatched request", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cassette.json")
+		os.WriteFile(path, []byte("[]"), 0644)
+
+		player, err := NewCassette(path, CassetteReplay)
+		if err != nil {
+			t.Fatalf("NewCassette failed with an error %v", err)
+		}
+
+		endpoint := "http://127.0.0.1:0"
+		_, err = client.NewClient(client.Config{
+			Endpoint:    &endpoint,
+			Middlewares: []client.Middleware{player.Middleware()},
+		}).CreateProcess(request)
+
+		if !errors.Is(err, ErrCassetteUnmatched) {
+			t.Fatalf("Error was expected to be ErrCassetteUnmatched got %v", err)
+		}
+		if len(player.Unmatched()) != 1 {
+			t.Fatalf("Unmatched requests were incorrect got %v", player.Unmatched())
+		}
+	})
+}
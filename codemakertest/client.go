@@ -0,0 +1,82 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package codemakertest
+
+import (
+	"context"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+// Client is an in-memory client.Client. Each operation calls its Func when set, otherwise it is served by the
+// Backend.
+type Client struct {
+	Backend *Backend
+
+	CreateProcessFunc    func(ctx context.Context, request *client.CreateProcessRequest) (*client.CreateProcessResponse, error)
+	GetProcessStatusFunc func(ctx context.Context, request *client.GetProcessStatusRequest) (*client.GetProcessStatusResponse, error)
+	GetProcessOutputFunc func(ctx context.Context, request *client.GetProcessOutputRequest) (*client.GetProcessOutputResponse, error)
+	CancelProcessFunc    func(ctx context.Context, request *client.CancelProcessRequest) (*client.CancelProcessResponse, error)
+}
+
+func NewClient() *Client {
+	return &Client{
+		Backend: NewBackend(),
+	}
+}
+
+func (c *Client) CreateProcess(request *client.CreateProcessRequest) (*client.CreateProcessResponse, error) {
+	return c.CreateProcessWithContext(context.Background(), request)
+}
+
+func (c *Client) GetProcessStatus(request *client.GetProcessStatusRequest) (*client.GetProcessStatusResponse, error) {
+	return c.GetProcessStatusWithContext(context.Background(), request)
+}
+
+func (c *Client) GetProcessOutput(request *client.GetProcessOutputRequest) (*client.GetProcessOutputResponse, error) {
+	return c.GetProcessOutputWithContext(context.Background(), request)
+}
+
+func (c *Client) CancelProcess(request *client.CancelProcessRequest) (*client.CancelProcessResponse, error) {
+	return c.CancelProcessWithContext(context.Background(), request)
+}
+
+func (c *Client) CreateProcessWithContext(ctx context.Context, request *client.CreateProcessRequest) (*client.CreateProcessResponse, error) {
+	if request == nil {
+		request = &client.CreateProcessRequest{}
+	}
+	if c.CreateProcessFunc != nil {
+		return c.CreateProcessFunc(ctx, request)
+	}
+	return c.Backend.CreateProcess(ctx, request)
+}
+
+func (c *Client) GetProcessStatusWithContext(ctx context.Context, request *client.GetProcessStatusRequest) (*client.GetProcessStatusResponse, error) {
+	if request == nil {
+		request = &client.GetProcessStatusRequest{}
+	}
+	if c.GetProcessStatusFunc != nil {
+		return c.GetProcessStatusFunc(ctx, request)
+	}
+	return c.Backend.GetProcessStatus(ctx, request)
+}
+
+func (c *Client) GetProcessOutputWithContext(ctx context.Context, request *client.GetProcessOutputRequest) (*client.GetProcessOutputResponse, error) {
+	if request == nil {
+		request = &client.GetProcessOutputRequest{}
+	}
+	if c.GetProcessOutputFunc != nil {
+		return c.GetProcessOutputFunc(ctx, request)
+	}
+	return c.Backend.GetProcessOutput(ctx, request)
+}
+
+func (c *Client) CancelProcessWithContext(ctx context.Context, request *client.CancelProcessRequest) (*client.CancelProcessResponse, error) {
+	if request == nil {
+		request = &client.CancelProcessRequest{}
+	}
+	if c.CancelProcessFunc != nil {
+		return c.CancelProcessFunc(ctx, request)
+	}
+	return c.Backend.CancelProcess(ctx, request)
+}
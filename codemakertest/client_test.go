@@ -0,0 +1,120 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package codemakertest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+func pollConfig() client.PollConfig {
+	interval := time.Millisecond
+	return client.PollConfig{
+		Interval: &interval,
+	}
+}
+
+func TestClient(t *testing.T) {
+
+	t.Run("Client simulates completed process", func(t *testing.T) {
+		c := NewClient()
+
+		got, err := client.RunProcess(context.Background(), c, &client.CreateProcessRequest{
+			Process: client.Process{
+				Mode:     client.ModeDocument,
+				Language: client.LanguageGo,
+				Input: client.Input{
+					Source: "package main",
+				},
+			},
+		}, pollConfig())
+		if err != nil {
+			t.Fatalf("RunProcess failed with an error %v", err)
+		}
+
+		if got.Source != "package main" {
+			t.Fatalf("Output source was incorrect got %s", got.Source)
+		}
+
+		processes := c.Backend.Processes()
+		if len(processes) != 1 || processes[0].Mode != client.ModeDocument {
+			t.Fatalf("Recorded processes were incorrect got %v", processes)
+		}
+	})
+
+	t.Run("Client simulates failed process", func(t *testing.T) {
+		c := NewClient()
+		c.Backend.SetLifecycle(func(process client.Process) Lifecycle {
+			return Lifecycle{
+				Statuses: []client.Status{client.StatusInProgress, client.StatusFailed},
+			}
+		})
+
+		_, err := client.RunProcess(context.Background(), c, &client.CreateProcessRequest{}, pollConfig())
+
+		if !errors.Is(err, client.ErrProcessFailed) {
+			t.Fatalf("Error was expected to be ErrProcessFailed got %v", err)
+		}
+	})
+
+	t.Run("Client returns injected errors", func(t *testing.T) {
+		c := NewClient()
+		c.Backend.FailNext(client.OperationCreateProcess, http.StatusUnauthorized, "UNAUTHORIZED", "Unauthorized.")
+
+		_, err := c.CreateProcess(&client.CreateProcessRequest{})
+		if !errors.Is(err, client.ErrUnauthorized) {
+			t.Fatalf("Error was expected to be ErrUnauthorized got %v", err)
+		}
+
+		if _, err := c.CreateProcess(&client.CreateProcessRequest{}); err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+	})
+
+	t.Run("Client uses scripted function", func(t *testing.T) {
+		c := NewClient()
+		c.GetProcessStatusFunc = func(ctx context.Context, request *client.GetProcessStatusRequest) (*client.GetProcessStatusResponse, error) {
+			return &client.GetProcessStatusResponse{Status: client.StatusTimedOut}, nil
+		}
+
+		got, err := c.GetProcessStatus(&client.GetProcessStatusRequest{Id: "id"})
+		if err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		if got.Status != client.StatusTimedOut {
+			t.Fatalf("Status was incorrect got %s", got.Status)
+		}
+	})
+	t.Run("Client passes empty request to scripted function", func(t *testing.T) {
+		c := NewClient()
+		c.CreateProcessFunc = func(ctx context.Context, request *client.CreateProcessRequest) (*client.CreateProcessResponse, error) {
+			if request == nil {
+				t.Fatalf("Request was expected not to be nil")
+			}
+			return &client.CreateProcessResponse{Id: "id"}, nil
+		}
+
+		if _, err := c.CreateProcess(nil); err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+	})
+	t.Run("Backend rejects nil requests without panicking", func(t *testing.T) {
+		b := NewBackend()
+
+		if _, err := b.GetProcessStatus(context.Background(), nil); !errors.Is(err, client.ErrNotFound) {
+			t.Fatalf("Error was expected to be ErrNotFound got %v", err)
+		}
+		if _, err := b.GetProcessOutput(context.Background(), nil); !errors.Is(err, client.ErrNotFound) {
+			t.Fatalf("Error was expected to be ErrNotFound got %v", err)
+		}
+		if _, err := b.CancelProcess(context.Background(), nil); !errors.Is(err, client.ErrNotFound) {
+			t.Fatalf("Error was expected to be ErrNotFound got %v", err)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package codemakertest
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+const (
+	headerRequestId       = "X-Request-Id"
+	headerRetryAfter      = "Retry-After"
+	headerContentEncoding = "Content-Encoding"
+)
+
+// Server is a fake CodeMaker API served by the Backend over HTTP.
+type Server struct {
+	*httptest.Server
+	Backend *Backend
+}
+
+func NewServer() *Server {
+	s := &Server{
+		Backend: NewBackend(),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client creates a client.Client connected to the server.
+func (s *Server) Client() client.Client {
+	return s.ClientWithConfig(client.Config{})
+}
+
+// ClientWithConfig creates a client.Client connected to the server, overriding the endpoint of the config.
+func (s *Server) ClientWithConfig(config client.Config) client.Client {
+	endpoint := s.URL
+	config.Endpoint = &endpoint
+	return client.NewClient(config)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := s.readBody(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	var response interface{}
+	switch r.URL.Path {
+	case "/process":
+		request := &client.CreateProcessRequest{}
+		if err = s.decode(body, request); err == nil {
+			response, err = s.Backend.CreateProcess(r.Context(), request)
+		}
+	case "/process/status":
+		request := &client.GetProcessStatusRequest{}
+		if err = s.decode(body, request); err == nil {
+			response, err = s.Backend.GetProcessStatus(r.Context(), request)
+		}
+	case "/process/output":
+		request := &client.GetProcessOutputRequest{}
+		if err = s.decode(body, request); err == nil {
+			response, err = s.Backend.GetProcessOutput(r.Context(), request)
+		}
+	case "/process/cancel":
+		request := &client.CancelProcessRequest{}
+		if err = s.decode(body, request); err == nil {
+			response, err = s.Backend.CancelProcess(r.Context(), request)
+		}
+	default:
+		err = s.Backend.error(http.StatusNotFound, ErrorCodeNotFound, "Resource not found.")
+	}
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == "/process" {
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// readBody reads the request body, decompressing it when it is gzip encoded.
+func (s *Server) readBody(r *http.Request) ([]byte, error) {
+	var reader io.Reader = r.Body
+	switch r.Header.Get(headerContentEncoding) {
+	case "", "identity":
+	case "gzip":
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, s.Backend.error(http.StatusBadRequest, ErrorCodeBadRequest, "Failed to read request.")
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	default:
+		return nil, s.Backend.error(http.StatusUnsupportedMediaType, ErrorCodeBadRequest, "Unsupported content encoding.")
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, s.Backend.error(http.StatusBadRequest, ErrorCodeBadRequest, "Failed to read request.")
+	}
+	return body, nil
+}
+
+func (s *Server) decode(body []byte, request interface{}) error {
+	if err := json.Unmarshal(body, request); err != nil {
+		return s.Backend.error(http.StatusBadRequest, ErrorCodeBadRequest, "Invalid request payload.")
+	}
+	return nil
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+		apiErr = s.Backend.error(http.StatusInternalServerError, "SERVICE_ERROR", err.Error())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(headerRequestId, apiErr.RequestId)
+	if apiErr.StatusCode == http.StatusTooManyRequests {
+		w.Header().Set(headerRetryAfter, fmt.Sprint(int(math.Ceil(s.Backend.retryAfter().Seconds()))))
+	}
+	w.WriteHeader(apiErr.StatusCode)
+	json.NewEncoder(w).Encode(&client.Error{
+		Code:    apiErr.Code,
+		Message: apiErr.Message,
+	})
+}
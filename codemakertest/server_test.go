@@ -0,0 +1,168 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package codemakertest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+func TestServer(t *testing.T) {
+
+	t.Run("Server simulates process lifecycle", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+
+		got, err := client.RunProcess(context.Background(), s.Client(), &client.CreateProcessRequest{
+			Process: client.Process{
+				Mode:     client.ModeUnitTest,
+				Language: client.LanguageJava,
+				Input: client.Input{
+					Source: "class Main {}",
+				},
+			},
+		}, pollConfig())
+		if err != nil {
+			t.Fatalf("RunProcess failed with an error %v", err)
+		}
+
+		if got.Source != "class Main {}" {
+			t.Fatalf("Output source was incorrect got %s", got.Source)
+		}
+
+		processes := s.Backend.Processes()
+		if len(processes) != 1 || processes[0].Language != client.LanguageJava {
+			t.Fatalf("Recorded processes were incorrect got %v", processes)
+		}
+	})
+
+	t.Run("Server returns injected error codes", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+
+		s.Backend.FailNext(client.OperationGetProcessStatus, http.StatusInternalServerError, "SERVICE_ERROR", "Unknown service error")
+
+		_, err := s.Client().GetProcessStatus(&client.GetProcessStatusRequest{Id: "id"})
+
+		var apiErr *client.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Error was expected to be APIError got %v", err)
+		}
+		if apiErr.StatusCode != http.StatusInternalServerError || apiErr.Code != "SERVICE_ERROR" || apiErr.RequestId == "" {
+			t.Fatalf("Error was incorrect got %v", apiErr)
+		}
+	})
+
+	t.Run("Server simulates rate limiting", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+
+		s.Backend.SetRateLimit(1, time.Minute)
+		c := s.Client()
+
+		if _, err := c.CreateProcess(&client.CreateProcessRequest{}); err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		_, err := c.CreateProcess(&client.CreateProcessRequest{})
+		if !errors.Is(err, client.ErrRateLimited) {
+			t.Fatalf("Error was expected to be ErrRateLimited got %v", err)
+		}
+	})
+
+	t.Run("Server simulates latency", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+
+		s.Backend.SetLatency(200 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := s.Client().CreateProcessWithContext(ctx, &client.CreateProcessRequest{})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Error was expected to be context.DeadlineExceeded got %v", err)
+		}
+	})
+
+	t.Run("Server records cancelled processes", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+
+		c := s.Client()
+		created, err := c.CreateProcess(&client.CreateProcessRequest{})
+		if err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		if _, err := c.CancelProcess(&client.CancelProcessRequest{Id: created.Id}); err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		if status, _ := s.Backend.Status(created.Id); status != client.StatusCancelled {
+			t.Fatalf("Status was incorrect got %s", status)
+		}
+	})
+
+	t.Run("Server decodes compressed requests", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+
+		threshold := 0
+		c := s.ClientWithConfig(client.Config{
+			CompressRequests:     true,
+			CompressionThreshold: &threshold,
+			Middlewares: []client.Middleware{func(next client.Handler) client.Handler {
+				return func(call *client.Call) (*http.Response, error) {
+					if call.HttpRequest.Header.Get("Content-Encoding") != "gzip" {
+						t.Errorf("Request was expected to be compressed got %v", call.HttpRequest.Header)
+					}
+					return next(call)
+				}
+			}},
+		})
+
+		source := strings.Repeat("class Main {}\n", 100)
+		if _, err := c.CreateProcess(&client.CreateProcessRequest{
+			Process: client.Process{
+				Mode:     client.ModeDocument,
+				Language: client.LanguageJava,
+				Input:    client.Input{Source: source},
+			},
+		}); err != nil {
+			t.Fatalf("Request failed with an error %v", err)
+		}
+
+		processes := s.Backend.Processes()
+		if len(processes) != 1 || processes[0].Input.Source != source {
+			t.Fatalf("Recorded processes were incorrect got %v", processes)
+		}
+	})
+
+	t.Run("Server rejects unsupported content encoding", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+
+		c := s.ClientWithConfig(client.Config{
+			Middlewares: []client.Middleware{func(next client.Handler) client.Handler {
+				return func(call *client.Call) (*http.Response, error) {
+					call.HttpRequest.Header.Set("Content-Encoding", "br")
+					return next(call)
+				}
+			}},
+		})
+
+		_, err := c.GetProcessStatus(&client.GetProcessStatusRequest{Id: "id"})
+
+		var apiErr *client.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnsupportedMediaType {
+			t.Fatalf("Error was incorrect got %v", err)
+		}
+	})
+}
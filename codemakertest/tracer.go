@@ -0,0 +1,134 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package codemakertest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+// HeaderTraceParent is the W3C Trace Context header injected by the TraceRecorder.
+const HeaderTraceParent = "traceparent"
+
+// RecordedSpan is a span captured by the TraceRecorder.
+type RecordedSpan struct {
+	Name       string
+	TraceId    string
+	SpanId     string
+	ParentId   string
+	Attributes map[string]interface{}
+	Err        error
+	StartedAt  time.Time
+	EndedAt    time.Time
+}
+
+// Ended returns true when the span has been ended.
+func (s RecordedSpan) Ended() bool {
+	return !s.EndedAt.IsZero()
+}
+
+type spanContextKey struct{}
+
+// TraceRecorder is an in-memory client.Tracer recording every span. It is safe for concurrent use.
+type TraceRecorder struct {
+	mu    sync.Mutex
+	ids   uint64
+	spans []*RecordedSpan
+}
+
+func NewTraceRecorder() *TraceRecorder {
+	return &TraceRecorder{}
+}
+
+// Spans returns copies of the recorded spans in the order they were started.
+func (r *TraceRecorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spans := make([]RecordedSpan, 0, len(r.spans))
+	for _, span := range r.spans {
+		copied := *span
+		copied.Attributes = map[string]interface{}{}
+		for key, value := range span.Attributes {
+			copied.Attributes[key] = value
+		}
+		spans = append(spans, copied)
+	}
+	return spans
+}
+
+// SpansNamed returns the recorded spans with the name.
+func (r *TraceRecorder) SpansNamed(name string) []RecordedSpan {
+	var spans []RecordedSpan
+	for _, span := range r.Spans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func (r *TraceRecorder) Start(ctx context.Context, name string, attributes ...client.Attribute) (context.Context, client.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ids++
+	span := &RecordedSpan{
+		Name:       name,
+		TraceId:    fmt.Sprintf("%032x", r.ids),
+		SpanId:     fmt.Sprintf("%016x", r.ids),
+		Attributes: map[string]interface{}{},
+		StartedAt:  time.Now(),
+	}
+	if parent, ok := ctx.Value(spanContextKey{}).(*RecordedSpan); ok {
+		span.TraceId = parent.TraceId
+		span.ParentId = parent.SpanId
+	}
+	for _, attribute := range attributes {
+		span.Attributes[attribute.Key] = attribute.Value
+	}
+	r.spans = append(r.spans, span)
+
+	return context.WithValue(ctx, spanContextKey{}, span), &recordingSpan{recorder: r, span: span}
+}
+
+func (r *TraceRecorder) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(spanContextKey{}).(*RecordedSpan); ok {
+		header.Set(HeaderTraceParent, fmt.Sprintf("00-%s-%s-01", span.TraceId, span.SpanId))
+	}
+}
+
+type recordingSpan struct {
+	recorder *TraceRecorder
+	span     *RecordedSpan
+}
+
+func (s *recordingSpan) SetAttributes(attributes ...client.Attribute) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	for _, attribute := range attributes {
+		s.span.Attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.span.Err = err
+}
+
+func (s *recordingSpan) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	if s.span.EndedAt.IsZero() {
+		s.span.EndedAt = time.Now()
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 CodeMaker AI Inc. All rights reserved.
+
+package codemakertest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/codemakerai/codemaker-sdk-go/client"
+)
+
+func TestTraceRecorder(t *testing.T) {
+
+	process := client.Process{
+		Mode:     client.ModeDocument,
+		Language: client.LanguageGo,
+		Input: client.Input{
+			Source: "package main",
+		},
+	}
+
+	t.Run("Client records spans for calls and process lifecycle", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+
+		var mu sync.Mutex
+		var headers []string
+		recorder := NewTraceRecorder()
+		c := s.ClientWithConfig(client.Config{
+			Tracer: recorder,
+			Middlewares: []client.Middleware{
+				func(next client.Handler) client.Handler {
+					return func(call *client.Call) (*http.Response, error) {
+						mu.Lock()
+						headers = append(headers, call.HttpRequest.Header.Get(HeaderTraceParent))
+						mu.Unlock()
+						return next(call)
+					}
+				},
+			},
+		})
+
+		ctx, root := recorder.Start(context.Background(), "root")
+		_, err := client.RunProcess(ctx, c, &client.CreateProcessRequest{Process: process}, pollConfig())
+		if err != nil {
+			t.Fatalf("RunProcess failed with an error %v", err)
+		}
+		root.End()
+
+		created := recorder.SpansNamed("codemaker." + client.OperationCreateProcess)
+		if len(created) != 1 {
+			t.Fatalf("CreateProcess span count was incorrect got %d", len(created))
+		}
+		span := created[0]
+		if span.Attributes[client.AttributeMode] != client.ModeDocument.String() ||
+			span.Attributes[client.AttributeLanguage] != client.LanguageGo.String() ||
+			span.Attributes[client.AttributeSourceSize] != len("package main") ||
+			span.Attributes[client.AttributeHttpStatusCode] != http.StatusCreated ||
+			span.Attributes[client.AttributeProcessId] == "" {
+			t.Fatalf("CreateProcess span attributes were incorrect got %v", span.Attributes)
+		}
+
+		statuses := recorder.SpansNamed("codemaker." + client.OperationGetProcessStatus)
+		if len(statuses) != 2 || statuses[1].Attributes[client.AttributeStatus] != client.StatusCompleted.String() {
+			t.Fatalf("GetProcessStatus spans were incorrect got %v", statuses)
+		}
+
+		lifecycle := recorder.SpansNamed(client.SpanProcess)
+		if len(lifecycle) != 1 || !lifecycle[0].Ended() || lifecycle[0].Err != nil ||
+			lifecycle[0].Attributes[client.AttributeStatus] != client.StatusCompleted.String() {
+			t.Fatalf("Process span was incorrect got %v", lifecycle)
+		}
+
+		rootSpan := recorder.SpansNamed("root")[0]
+		for _, span := range recorder.Spans() {
+			if !span.Ended() {
+				t.Fatalf("Span %s was expected to be ended", span.Name)
+			}
+			if span.Name != "root" && (span.TraceId != rootSpan.TraceId || span.ParentId != rootSpan.SpanId) {
+				t.Fatalf("Span %s was expected to be a child of the root span", span.Name)
+			}
+		}
+
+		if len(headers) == 0 {
+			t.Fatalf("Requests were expected to be made")
+		}
+		for _, header := range headers {
+			if !strings.HasPrefix(header, "00-"+rootSpan.TraceId+"-") {
+				t.Fatalf("Trace header was incorrect got %s", header)
+			}
+		}
+	})
+
+	t.Run("Client records failed calls and processes", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		s.Backend.SetLifecycle(func(process client.Process) Lifecycle {
+			return Lifecycle{Statuses: []client.Status{client.StatusFailed}}
+		})
+
+		recorder := NewTraceRecorder()
+		c := s.ClientWithConfig(client.Config{Tracer: recorder})
+
+		_, err := client.RunProcess(context.Background(), c, &client.CreateProcessRequest{Process: process}, pollConfig())
+		if !errors.Is(err, client.ErrProcessFailed) {
+			t.Fatalf("RunProcess was expected to fail got %v", err)
+		}
+
+		lifecycle := recorder.SpansNamed(client.SpanProcess)
+		if len(lifecycle) != 1 || !errors.Is(lifecycle[0].Err, client.ErrProcessFailed) {
+			t.Fatalf("Process span was expected to record the failure got %v", lifecycle)
+		}
+
+		_, err = c.GetProcessOutput(&client.GetProcessOutputRequest{Id: "unknown"})
+		outputs := recorder.SpansNamed("codemaker." + client.OperationGetProcessOutput)
+		if err == nil || len(outputs) != 1 || outputs[0].Err == nil ||
+			outputs[0].Attributes[client.AttributeHttpStatusCode] != http.StatusNotFound ||
+			outputs[0].Attributes[client.AttributeRequestId] == "" {
+			t.Fatalf("GetProcessOutput span was expected to record the failure got %v", outputs)
+		}
+	})
+}